@@ -41,7 +41,10 @@ func main() {
 	defer cancel()
 
 	application := app.NewApp(logg)
-	monitorService := service.NewMonitorService(ctx, application, logg, cfg)
+	monitorService, err := service.NewMonitorService(ctx, application, logg, cfg)
+	if err != nil {
+		logg.Fatalf("System-monitor init error: %v", err)
+	}
 
 	logg.Debugf("Starting system-monitor...")
 	if err = monitorService.Run(ctx); err != nil {
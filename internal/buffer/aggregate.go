@@ -0,0 +1,572 @@
+package buffer
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+// Aggregate summarizes the samples of a window. Avg, Min and Max are reduced
+// per sub-metric, keyed by identity (device, mount point, protocol...).
+type Aggregate struct {
+	Samples int
+	From    time.Time
+	To      time.Time
+
+	Avg  *entity.SystemMetrics
+	Min  *entity.SystemMetrics
+	Max  *entity.SystemMetrics
+	Last *entity.SystemMetrics
+}
+
+// reducer folds the values of one sub-metric. Gauges and rates use value,
+// per-sample counters (bytes seen since the previous sample) use count.
+type reducer struct {
+	value func([]float64) float64
+	count func([]float64) float64
+}
+
+var (
+	avgReducer = reducer{value: mean, count: sum}
+	minReducer = reducer{value: minimum, count: minimum}
+	maxReducer = reducer{value: maximum, count: maximum}
+)
+
+func AggregateWindow(samples []*entity.SystemMetrics) Aggregate {
+	if len(samples) == 0 {
+		return Aggregate{}
+	}
+
+	return Aggregate{
+		Samples: len(samples),
+		From:    samples[0].Timestamp,
+		To:      samples[len(samples)-1].Timestamp,
+		Avg:     reduce(samples, avgReducer),
+		Min:     reduce(samples, minReducer),
+		Max:     reduce(samples, maxReducer),
+		Last:    latest(samples),
+	}
+}
+
+// latest merges the most recent value of every part of the snapshot. Samples
+// of collectors with different periods each hold only their own part.
+func latest(samples []*entity.SystemMetrics) *entity.SystemMetrics {
+	m := &entity.SystemMetrics{Timestamp: samples[len(samples)-1].Timestamp}
+	for _, s := range samples {
+		if s.LoadAverage != nil {
+			m.LoadAverage = s.LoadAverage
+		}
+		if s.CPU != nil {
+			m.CPU, m.CPUUsagePercent = s.CPU, s.CPUUsagePercent
+		}
+		if s.Memory != nil {
+			m.Memory, m.MemoryUsedMB = s.Memory, s.MemoryUsedMB
+		}
+		if s.Pressure != nil {
+			m.Pressure = s.Pressure
+		}
+		if s.Cgroups != nil {
+			m.Cgroups = s.Cgroups
+		}
+		if s.Disks != nil {
+			m.Disks = s.Disks
+		}
+		if s.Filesystems != nil {
+			m.Filesystems, m.DiskUsedPercent = s.Filesystems, s.DiskUsedPercent
+		}
+		if s.Interfaces != nil {
+			m.Interfaces = s.Interfaces
+		}
+		if s.Protocols != nil {
+			m.Protocols, m.ProtocolSource = s.Protocols, s.ProtocolSource
+		}
+		if s.Connections != nil {
+			m.Connections = s.Connections
+		}
+		if s.TopTalkers != nil {
+			m.TopTalkers = s.TopTalkers
+		}
+		if s.TCPStates != nil {
+			m.TCPStates = s.TCPStates
+		}
+	}
+	m.Custom = lastCustom(samples)
+	m.Statuses = lastStatuses(samples)
+	return m
+}
+
+func reduce(samples []*entity.SystemMetrics, r reducer) *entity.SystemMetrics {
+	return &entity.SystemMetrics{
+		Timestamp: samples[len(samples)-1].Timestamp,
+
+		CPUUsagePercent: r.value(values(
+			filter(samples, func(m *entity.SystemMetrics) bool { return m.CPU != nil }),
+			func(m *entity.SystemMetrics) float64 { return m.CPUUsagePercent })),
+		MemoryUsedMB: round(r.value(values(
+			filter(samples, func(m *entity.SystemMetrics) bool { return m.Memory != nil }),
+			func(m *entity.SystemMetrics) float64 { return float64(m.MemoryUsedMB) }))),
+		DiskUsedPercent: r.value(values(
+			filter(samples, func(m *entity.SystemMetrics) bool { return m.Filesystems != nil }),
+			func(m *entity.SystemMetrics) float64 { return m.DiskUsedPercent })),
+
+		LoadAverage:    reduceLoadAverage(samples, r),
+		CPU:            reduceCPU(samples, r),
+		Memory:         reduceMemory(samples, r),
+		Pressure:       reducePressure(samples, r),
+		Cgroups:        reduceCgroups(samples, r),
+		Disks:          reduceDisks(samples, r),
+		Filesystems:    reduceFilesystems(samples, r),
+		Interfaces:     reduceInterfaces(samples, r),
+		Protocols:      reduceProtocols(samples, r),
+		ProtocolSource: lastProtocolSource(samples),
+		Connections:    lastConnections(samples),
+		TopTalkers:     reduceTopTalkers(samples, r),
+		TCPStates:      reduceTCPStates(samples, r),
+		Custom:         reduceCustom(samples, r),
+		Statuses:       lastStatuses(samples),
+	}
+}
+
+func reduceLoadAverage(samples []*entity.SystemMetrics, r reducer) *entity.LoadAverage {
+	items := sections(samples, func(m *entity.SystemMetrics) *entity.LoadAverage { return m.LoadAverage })
+	if len(items) == 0 {
+		return nil
+	}
+	return &entity.LoadAverage{
+		OneMin:      r.value(values(items, func(l *entity.LoadAverage) float64 { return l.OneMin })),
+		FiveMins:    r.value(values(items, func(l *entity.LoadAverage) float64 { return l.FiveMins })),
+		FifteenMins: r.value(values(items, func(l *entity.LoadAverage) float64 { return l.FifteenMins })),
+		RunningTasks: uint32(round(r.value(values(items, func(l *entity.LoadAverage) float64 {
+			return float64(l.RunningTasks)
+		})))),
+		TotalTasks: uint32(round(r.value(values(items, func(l *entity.LoadAverage) float64 {
+			return float64(l.TotalTasks)
+		})))),
+	}
+}
+
+func reduceCPU(samples []*entity.SystemMetrics, r reducer) *entity.CPUUsage {
+	items := sections(samples, func(m *entity.SystemMetrics) *entity.CPUUsage { return m.CPU })
+	if len(items) == 0 {
+		return nil
+	}
+	return &entity.CPUUsage{
+		User:    r.value(values(items, func(c *entity.CPUUsage) float64 { return c.User })),
+		System:  r.value(values(items, func(c *entity.CPUUsage) float64 { return c.System })),
+		Idle:    r.value(values(items, func(c *entity.CPUUsage) float64 { return c.Idle })),
+		Nice:    r.value(values(items, func(c *entity.CPUUsage) float64 { return c.Nice })),
+		IOWait:  r.value(values(items, func(c *entity.CPUUsage) float64 { return c.IOWait })),
+		IRQ:     r.value(values(items, func(c *entity.CPUUsage) float64 { return c.IRQ })),
+		SoftIRQ: r.value(values(items, func(c *entity.CPUUsage) float64 { return c.SoftIRQ })),
+		Steal:   r.value(values(items, func(c *entity.CPUUsage) float64 { return c.Steal })),
+		Cores:   reduceCPUCores(items, r),
+	}
+}
+
+func reduceMemory(samples []*entity.SystemMetrics, r reducer) *entity.MemoryStats {
+	items := sections(samples, func(m *entity.SystemMetrics) *entity.MemoryStats { return m.Memory })
+	if len(items) == 0 {
+		return nil
+	}
+	mb := func(get func(*entity.MemoryStats) uint64) uint64 {
+		return round(r.value(values(items, func(m *entity.MemoryStats) float64 { return float64(get(m)) })))
+	}
+	return &entity.MemoryStats{
+		TotalMB:         mb(func(m *entity.MemoryStats) uint64 { return m.TotalMB }),
+		AvailableMB:     mb(func(m *entity.MemoryStats) uint64 { return m.AvailableMB }),
+		UsedMB:          mb(func(m *entity.MemoryStats) uint64 { return m.UsedMB }),
+		BuffCacheMB:     mb(func(m *entity.MemoryStats) uint64 { return m.BuffCacheMB }),
+		DirtyMB:         mb(func(m *entity.MemoryStats) uint64 { return m.DirtyMB }),
+		SwapTotalMB:     mb(func(m *entity.MemoryStats) uint64 { return m.SwapTotalMB }),
+		SwapUsedMB:      mb(func(m *entity.MemoryStats) uint64 { return m.SwapUsedMB }),
+		SwapInKBPerSec:  r.value(values(items, func(m *entity.MemoryStats) float64 { return m.SwapInKBPerSec })),
+		SwapOutKBPerSec: r.value(values(items, func(m *entity.MemoryStats) float64 { return m.SwapOutKBPerSec })),
+	}
+}
+
+// reducePressure folds the stall percentages and the averages of the kernel of
+// every resource; the stall time counters are taken from the latest sample.
+func reducePressure(samples []*entity.SystemMetrics, r reducer) []entity.PressureStats {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.PressureStats { return m.Pressure },
+		func(p entity.PressureStats) string { return p.Resource },
+		func(group []entity.PressureStats) entity.PressureStats {
+			line := func(get func(entity.PressureStats) entity.PressureLine) entity.PressureLine {
+				value := func(field func(entity.PressureLine) float64) float64 {
+					return r.value(values(group, func(p entity.PressureStats) float64 { return field(get(p)) }))
+				}
+				return entity.PressureLine{
+					Percent:   value(func(l entity.PressureLine) float64 { return l.Percent }),
+					Avg10:     value(func(l entity.PressureLine) float64 { return l.Avg10 }),
+					Avg60:     value(func(l entity.PressureLine) float64 { return l.Avg60 }),
+					Avg300:    value(func(l entity.PressureLine) float64 { return l.Avg300 }),
+					TotalUsec: get(group[len(group)-1]).TotalUsec,
+				}
+			}
+			return entity.PressureStats{
+				Resource: group[0].Resource,
+				Some:     line(func(p entity.PressureStats) entity.PressureLine { return p.Some }),
+				Full:     line(func(p entity.PressureStats) entity.PressureLine { return p.Full }),
+			}
+		})
+}
+
+// reduceCgroups folds every cgroup that was in the top of a sample of the
+// window, sorted by CPU. The memory limit is taken from the latest sample.
+func reduceCgroups(samples []*entity.SystemMetrics, r reducer) []entity.CgroupStats {
+	cgroups := reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.CgroupStats { return m.Cgroups },
+		func(g entity.CgroupStats) string { return g.Path },
+		func(group []entity.CgroupStats) entity.CgroupStats {
+			value := func(get func(entity.CgroupStats) float64) float64 {
+				return r.value(values(group, get))
+			}
+			return entity.CgroupStats{
+				Path:            group[0].Path,
+				CPUPercent:      value(func(g entity.CgroupStats) float64 { return g.CPUPercent }),
+				MemoryMB:        round(value(func(g entity.CgroupStats) float64 { return float64(g.MemoryMB) })),
+				MemoryLimitMB:   group[len(group)-1].MemoryLimitMB,
+				IOReadKBPerSec:  value(func(g entity.CgroupStats) float64 { return g.IOReadKBPerSec }),
+				IOWriteKBPerSec: value(func(g entity.CgroupStats) float64 { return g.IOWriteKBPerSec }),
+				Pids:            round(value(func(g entity.CgroupStats) float64 { return float64(g.Pids) })),
+			}
+		})
+
+	sort.SliceStable(cgroups, func(i, j int) bool {
+		return cgroups[i].CPUPercent > cgroups[j].CPUPercent
+	})
+	return cgroups
+}
+
+func reduceCPUCores(items []*entity.CPUUsage, r reducer) []entity.CPUCoreUsage {
+	var order []string
+	groups := make(map[string][]entity.CPUCoreUsage)
+	for _, usage := range items {
+		for _, core := range usage.Cores {
+			if _, ok := groups[core.Core]; !ok {
+				order = append(order, core.Core)
+			}
+			groups[core.Core] = append(groups[core.Core], core)
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	cores := make([]entity.CPUCoreUsage, 0, len(order))
+	for _, name := range order {
+		group := groups[name]
+		cores = append(cores, entity.CPUCoreUsage{
+			Core:    name,
+			User:    r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.User })),
+			System:  r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.System })),
+			Idle:    r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.Idle })),
+			Nice:    r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.Nice })),
+			IOWait:  r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.IOWait })),
+			IRQ:     r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.IRQ })),
+			SoftIRQ: r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.SoftIRQ })),
+			Steal:   r.value(values(group, func(c entity.CPUCoreUsage) float64 { return c.Steal })),
+		})
+	}
+	return cores
+}
+
+func reduceDisks(samples []*entity.SystemMetrics, r reducer) []entity.DiskStats {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.DiskStats { return m.Disks },
+		func(d entity.DiskStats) string { return d.Device },
+		func(group []entity.DiskStats) entity.DiskStats {
+			return entity.DiskStats{
+				Device:   group[0].Device,
+				TPS:      r.value(values(group, func(d entity.DiskStats) float64 { return d.TPS })),
+				KBPerSec: r.value(values(group, func(d entity.DiskStats) float64 { return d.KBPerSec })),
+			}
+		})
+}
+
+func reduceFilesystems(samples []*entity.SystemMetrics, r reducer) []entity.FsUsage {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.FsUsage { return m.Filesystems },
+		func(f entity.FsUsage) string { return f.MountPoint },
+		func(group []entity.FsUsage) entity.FsUsage {
+			return entity.FsUsage{
+				MountPoint:        group[0].MountPoint,
+				TotalMB:           round(r.value(values(group, func(f entity.FsUsage) float64 { return float64(f.TotalMB) }))),
+				UsedMB:            round(r.value(values(group, func(f entity.FsUsage) float64 { return float64(f.UsedMB) }))),
+				UsedPercent:       r.value(values(group, func(f entity.FsUsage) float64 { return f.UsedPercent })),
+				InodesTotal:       round(r.value(values(group, func(f entity.FsUsage) float64 { return float64(f.InodesTotal) }))),
+				InodesUsed:        round(r.value(values(group, func(f entity.FsUsage) float64 { return float64(f.InodesUsed) }))),
+				InodesUsedPercent: r.value(values(group, func(f entity.FsUsage) float64 { return f.InodesUsedPercent })),
+			}
+		})
+}
+
+// reduceInterfaces folds the rates of every interface; the link state and speed
+// are taken from its latest sample.
+func reduceInterfaces(samples []*entity.SystemMetrics, r reducer) []entity.InterfaceStats {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.InterfaceStats { return m.Interfaces },
+		func(s entity.InterfaceStats) string { return s.Name },
+		func(group []entity.InterfaceStats) entity.InterfaceStats {
+			rate := func(get func(entity.InterfaceStats) float64) float64 {
+				return r.value(values(group, get))
+			}
+			last := group[len(group)-1]
+			return entity.InterfaceStats{
+				Name:            last.Name,
+				State:           last.State,
+				SpeedMbps:       last.SpeedMbps,
+				RxBytesPerSec:   rate(func(s entity.InterfaceStats) float64 { return s.RxBytesPerSec }),
+				TxBytesPerSec:   rate(func(s entity.InterfaceStats) float64 { return s.TxBytesPerSec }),
+				RxPacketsPerSec: rate(func(s entity.InterfaceStats) float64 { return s.RxPacketsPerSec }),
+				TxPacketsPerSec: rate(func(s entity.InterfaceStats) float64 { return s.TxPacketsPerSec }),
+				RxErrorsPerSec:  rate(func(s entity.InterfaceStats) float64 { return s.RxErrorsPerSec }),
+				TxErrorsPerSec:  rate(func(s entity.InterfaceStats) float64 { return s.TxErrorsPerSec }),
+				RxDropsPerSec:   rate(func(s entity.InterfaceStats) float64 { return s.RxDropsPerSec }),
+				TxDropsPerSec:   rate(func(s entity.InterfaceStats) float64 { return s.TxDropsPerSec }),
+			}
+		})
+}
+
+func reduceProtocols(samples []*entity.SystemMetrics, r reducer) []entity.ProtocolTraffic {
+	protocols := reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.ProtocolTraffic { return m.Protocols },
+		func(p entity.ProtocolTraffic) string { return p.Protocol },
+		func(group []entity.ProtocolTraffic) entity.ProtocolTraffic {
+			return entity.ProtocolTraffic{
+				Protocol: group[0].Protocol,
+				Bytes:    round(r.count(values(group, func(p entity.ProtocolTraffic) float64 { return float64(p.Bytes) }))),
+			}
+		})
+
+	var total uint64
+	for _, p := range protocols {
+		total += p.Bytes
+	}
+	if total > 0 {
+		for idx := range protocols {
+			protocols[idx].Percent = float64(protocols[idx].Bytes) / float64(total) * 100
+		}
+	}
+	return protocols
+}
+
+// lastProtocolSource returns the source of the most recent protocol traffic.
+// The source only changes when capture starts or fails, so the window is
+// reported as measured by the latest one.
+func lastProtocolSource(samples []*entity.SystemMetrics) entity.TrafficSource {
+	for idx := len(samples) - 1; idx >= 0; idx-- {
+		if samples[idx].Protocols != nil {
+			return samples[idx].ProtocolSource
+		}
+	}
+	return ""
+}
+
+// lastConnections returns the most recent list of sockets: a socket table is a
+// state, not a measurement, so it is not averaged.
+func lastConnections(samples []*entity.SystemMetrics) []entity.NetworkConnection {
+	for idx := len(samples) - 1; idx >= 0; idx-- {
+		if samples[idx].Connections != nil {
+			return samples[idx].Connections
+		}
+	}
+	return nil
+}
+
+// reduceTopTalkers folds flows over the samples that have talkers. A flow that
+// is missing from a sample had no traffic then, so it counts as zero. The
+// result keeps as many flows as the largest sample.
+func reduceTopTalkers(samples []*entity.SystemMetrics, r reducer) []entity.TopTalker {
+	measured, top := 0, 0
+	for _, m := range samples {
+		if m.TopTalkers != nil {
+			measured++
+			top = max(top, len(m.TopTalkers))
+		}
+	}
+
+	talkers := reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.TopTalker { return m.TopTalkers },
+		func(t entity.TopTalker) string { return t.Protocol + " " + t.Src + " " + t.Dst },
+		func(group []entity.TopTalker) entity.TopTalker {
+			bps := values(group, func(t entity.TopTalker) float64 { return float64(t.BPS) })
+			bps = append(bps, make([]float64, max(0, measured-len(group)))...)
+			return entity.TopTalker{
+				Src:      group[0].Src,
+				Dst:      group[0].Dst,
+				Protocol: group[0].Protocol,
+				BPS:      round(r.value(bps)),
+			}
+		})
+
+	sort.SliceStable(talkers, func(i, j int) bool {
+		return talkers[i].BPS > talkers[j].BPS
+	})
+	if len(talkers) > top {
+		talkers = talkers[:top]
+	}
+	return talkers
+}
+
+func reduceTCPStates(samples []*entity.SystemMetrics, r reducer) []entity.TCPStateCount {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.TCPStateCount { return m.TCPStates },
+		func(s entity.TCPStateCount) string { return s.Family + " " + s.State },
+		func(group []entity.TCPStateCount) entity.TCPStateCount {
+			return entity.TCPStateCount{
+				Family: group[0].Family,
+				State:  group[0].State,
+				Count:  r.value(values(group, func(s entity.TCPStateCount) float64 { return s.Count })),
+			}
+		})
+}
+
+// reduceCustom folds the values of every custom metric, key by key, and keeps
+// the outcome of its latest run. As for statuses, a failed run does not hide
+// the values of the successful ones.
+func reduceCustom(samples []*entity.SystemMetrics, r reducer) []entity.CustomMetric {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.CustomMetric { return m.Custom },
+		func(c entity.CustomMetric) string { return c.Name },
+		func(group []entity.CustomMetric) entity.CustomMetric {
+			var order []string
+			runs := make(map[string][]float64)
+			for _, metric := range group {
+				for _, v := range metric.Values {
+					if _, ok := runs[v.Key]; !ok {
+						order = append(order, v.Key)
+					}
+					runs[v.Key] = append(runs[v.Key], v.Value)
+				}
+			}
+
+			metric := group[len(group)-1]
+			metric.Values = nil
+			for _, key := range order {
+				metric.Values = append(metric.Values, entity.CustomValue{Key: key, Value: r.value(runs[key])})
+			}
+			return metric
+		})
+}
+
+// lastCustom keeps the latest run of every custom metric.
+func lastCustom(samples []*entity.SystemMetrics) []entity.CustomMetric {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.CustomMetric { return m.Custom },
+		func(c entity.CustomMetric) string { return c.Name },
+		func(group []entity.CustomMetric) entity.CustomMetric { return group[len(group)-1] })
+}
+
+// lastStatuses keeps the most recent status of every category: a window with
+// some failed collections still reports the values of the successful ones.
+func lastStatuses(samples []*entity.SystemMetrics) []entity.CollectionStatus {
+	return reduceKeyed(samples,
+		func(m *entity.SystemMetrics) []entity.CollectionStatus { return m.Statuses },
+		func(s entity.CollectionStatus) string { return string(s.Category) },
+		func(group []entity.CollectionStatus) entity.CollectionStatus { return group[len(group)-1] })
+}
+
+func sections[T any](samples []*entity.SystemMetrics, get func(*entity.SystemMetrics) *T) []*T {
+	items := make([]*T, 0, len(samples))
+	for _, m := range samples {
+		if item := get(m); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// reduceKeyed groups the items of all samples by key and folds every group into
+// one item. The order of the first appearance of each key is preserved.
+func reduceKeyed[T any](
+	samples []*entity.SystemMetrics,
+	get func(*entity.SystemMetrics) []T,
+	key func(T) string,
+	fold func([]T) T,
+) []T {
+	var order []string
+	groups := make(map[string][]T)
+	for _, m := range samples {
+		for _, item := range get(m) {
+			k := key(item)
+			if _, ok := groups[k]; !ok {
+				order = append(order, k)
+			}
+			groups[k] = append(groups[k], item)
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	result := make([]T, 0, len(order))
+	for _, k := range order {
+		result = append(result, fold(groups[k]))
+	}
+	return result
+}
+
+func values[T any](items []T, get func(T) float64) []float64 {
+	result := make([]float64, len(items))
+	for idx, item := range items {
+		result[idx] = get(item)
+	}
+	return result
+}
+
+func sum(v []float64) float64 {
+	var total float64
+	for _, x := range v {
+		total += x
+	}
+	return total
+}
+
+func mean(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	return sum(v) / float64(len(v))
+}
+
+func minimum(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	result := v[0]
+	for _, x := range v[1:] {
+		result = math.Min(result, x)
+	}
+	return result
+}
+
+func maximum(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	result := v[0]
+	for _, x := range v[1:] {
+		result = math.Max(result, x)
+	}
+	return result
+}
+
+func round(v float64) uint64 {
+	if v <= 0 {
+		return 0
+	}
+	return uint64(math.Round(v))
+}
@@ -0,0 +1,285 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateWindow(t *testing.T) {
+	now := time.Now()
+	samples := []*entity.SystemMetrics{
+		{
+			Timestamp:   now.Add(-2 * time.Second),
+			LoadAverage: &entity.LoadAverage{OneMin: 1, FiveMins: 2, FifteenMins: 3},
+			CPU:         &entity.CPUUsage{User: 10, System: 5, Idle: 85},
+			Disks: []entity.DiskStats{
+				{Device: "sda", TPS: 10, KBPerSec: 100},
+			},
+			Filesystems: []entity.FsUsage{
+				{MountPoint: "/", TotalMB: 1000, UsedMB: 400, UsedPercent: 40},
+			},
+			Protocols: []entity.ProtocolTraffic{
+				{Protocol: "TCP", Bytes: 300},
+				{Protocol: "UDP", Bytes: 100},
+			},
+		},
+		{
+			Timestamp:   now.Add(-time.Second),
+			LoadAverage: &entity.LoadAverage{OneMin: 3, FiveMins: 4, FifteenMins: 5},
+			CPU:         &entity.CPUUsage{User: 30, System: 15, Idle: 55},
+			Disks: []entity.DiskStats{
+				{Device: "sdb", TPS: 1, KBPerSec: 8},
+				{Device: "sda", TPS: 20, KBPerSec: 300},
+			},
+			Filesystems: []entity.FsUsage{
+				{MountPoint: "/", TotalMB: 1000, UsedMB: 600, UsedPercent: 60},
+			},
+			Protocols: []entity.ProtocolTraffic{
+				{Protocol: "TCP", Bytes: 500},
+			},
+		},
+		{
+			Timestamp: now,
+		},
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.Equal(t, 3, agg.Samples)
+	require.Equal(t, samples[0].Timestamp, agg.From)
+	require.Equal(t, now, agg.To)
+	require.Equal(t, now, agg.Last.Timestamp)
+
+	require.Equal(t, &entity.LoadAverage{OneMin: 2, FiveMins: 3, FifteenMins: 4}, agg.Avg.LoadAverage)
+	require.Equal(t, &entity.CPUUsage{User: 20, System: 10, Idle: 70}, agg.Avg.CPU)
+	require.Equal(t, []entity.DiskStats{
+		{Device: "sda", TPS: 15, KBPerSec: 200},
+		{Device: "sdb", TPS: 1, KBPerSec: 8},
+	}, agg.Avg.Disks)
+	require.Equal(t, []entity.FsUsage{
+		{MountPoint: "/", TotalMB: 1000, UsedMB: 500, UsedPercent: 50},
+	}, agg.Avg.Filesystems)
+	require.Equal(t, []entity.ProtocolTraffic{
+		{Protocol: "TCP", Bytes: 800, Percent: 800.0 / 900 * 100},
+		{Protocol: "UDP", Bytes: 100, Percent: 100.0 / 900 * 100},
+	}, agg.Avg.Protocols)
+
+	require.Equal(t, &entity.CPUUsage{User: 10, System: 5, Idle: 55}, agg.Min.CPU)
+	require.Equal(t, &entity.CPUUsage{User: 30, System: 15, Idle: 85}, agg.Max.CPU)
+	require.Equal(t, uint64(500), agg.Max.Protocols[0].Bytes)
+	// The last sample is empty, so Last holds the latest value of every part.
+	require.Equal(t, samples[1].CPU, agg.Last.CPU)
+	require.Equal(t, samples[1].Disks, agg.Last.Disks)
+}
+
+func TestAggregateWindow_PartialSamples(t *testing.T) {
+	now := time.Now()
+	samples := []*entity.SystemMetrics{
+		{Timestamp: now.Add(-3 * time.Second), CPU: &entity.CPUUsage{Idle: 80}, CPUUsagePercent: 20},
+		{
+			Timestamp:    now.Add(-3 * time.Second),
+			Memory:       &entity.MemoryStats{TotalMB: 4096, UsedMB: 1000, SwapInKBPerSec: 10},
+			MemoryUsedMB: 1000,
+		},
+		{Timestamp: now.Add(-2 * time.Second), LoadAverage: &entity.LoadAverage{OneMin: 1}},
+		{Timestamp: now.Add(-time.Second), CPU: &entity.CPUUsage{Idle: 60}, CPUUsagePercent: 40},
+		{
+			Timestamp:    now.Add(-time.Second),
+			Memory:       &entity.MemoryStats{TotalMB: 4096, UsedMB: 3001, SwapInKBPerSec: 30},
+			MemoryUsedMB: 3001,
+		},
+		{
+			Timestamp:       now,
+			Filesystems:     []entity.FsUsage{{MountPoint: "/", UsedPercent: 50}},
+			DiskUsedPercent: 50,
+		},
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.InDelta(t, 30, agg.Avg.CPUUsagePercent, 1e-9)
+	require.InDelta(t, 20, agg.Min.CPUUsagePercent, 1e-9)
+	require.InDelta(t, 50, agg.Avg.DiskUsedPercent, 1e-9)
+	// Samples of other collectors carry no memory, they do not lower the average.
+	require.Equal(t, uint64(2001), agg.Avg.MemoryUsedMB)
+	require.Equal(t, &entity.MemoryStats{TotalMB: 4096, UsedMB: 2001, SwapInKBPerSec: 20}, agg.Avg.Memory)
+	require.Equal(t, uint64(1000), agg.Min.Memory.UsedMB)
+
+	require.Equal(t, now, agg.Last.Timestamp)
+	require.Equal(t, &entity.CPUUsage{Idle: 60}, agg.Last.CPU)
+	require.InDelta(t, 40, agg.Last.CPUUsagePercent, 1e-9)
+	require.Equal(t, &entity.LoadAverage{OneMin: 1}, agg.Last.LoadAverage)
+	require.Equal(t, samples[4].Memory, agg.Last.Memory)
+	require.Equal(t, uint64(3001), agg.Last.MemoryUsedMB)
+	require.InDelta(t, 50, agg.Last.DiskUsedPercent, 1e-9)
+}
+
+func TestAggregateWindow_Statuses(t *testing.T) {
+	now := time.Now()
+	ok := func(c entity.Category) entity.CollectionStatus {
+		return entity.CollectionStatus{Category: c, State: entity.CollectionOK}
+	}
+	failed := entity.CollectionStatus{Category: entity.CategoryCPU, State: entity.CollectionError, Message: "read failed"}
+	samples := []*entity.SystemMetrics{
+		{
+			Timestamp: now.Add(-2 * time.Second),
+			CPU:       &entity.CPUUsage{Idle: 80},
+			Statuses:  []entity.CollectionStatus{ok(entity.CategoryCPU)},
+		},
+		{
+			Timestamp:   now.Add(-time.Second),
+			LoadAverage: &entity.LoadAverage{OneMin: 1},
+			Statuses:    []entity.CollectionStatus{ok(entity.CategoryLoadAverage)},
+		},
+		{Timestamp: now, Statuses: []entity.CollectionStatus{failed}},
+	}
+
+	agg := AggregateWindow(samples)
+
+	// The values of the window are kept, the status tells the latest outcome.
+	expected := []entity.CollectionStatus{failed, ok(entity.CategoryLoadAverage)}
+	require.Equal(t, &entity.CPUUsage{Idle: 80}, agg.Avg.CPU)
+	require.Equal(t, expected, agg.Avg.Statuses)
+	require.Equal(t, expected, agg.Last.Statuses)
+}
+
+func TestAggregateWindow_Custom(t *testing.T) {
+	now := time.Now()
+	run := func(values ...float64) entity.CustomMetric {
+		return entity.CustomMetric{
+			Name:   "queue",
+			Values: []entity.CustomValue{{Key: "active", Value: values[0]}, {Key: "deferred", Value: values[1]}},
+			State:  entity.CollectionOK,
+		}
+	}
+	failed := entity.CustomMetric{
+		Name: "queue", State: entity.CollectionError, Message: "exit status 1", ExitCode: 1, Stderr: "no queue",
+	}
+	backups := entity.CustomMetric{Name: "backups", Values: []entity.CustomValue{{Value: 3}}, State: entity.CollectionOK}
+	samples := []*entity.SystemMetrics{
+		{Timestamp: now.Add(-3 * time.Second), Custom: []entity.CustomMetric{run(10, 2)}},
+		{Timestamp: now.Add(-2 * time.Second), Custom: []entity.CustomMetric{backups}},
+		{Timestamp: now.Add(-time.Second), Custom: []entity.CustomMetric{run(20, 4)}},
+		{Timestamp: now, Custom: []entity.CustomMetric{failed}},
+	}
+
+	agg := AggregateWindow(samples)
+
+	avg := failed
+	avg.Values = []entity.CustomValue{{Key: "active", Value: 15}, {Key: "deferred", Value: 3}}
+	require.Equal(t, []entity.CustomMetric{avg, backups}, agg.Avg.Custom)
+	require.Equal(t, []entity.CustomValue{{Key: "active", Value: 20}, {Key: "deferred", Value: 4}}, agg.Max.Custom[0].Values)
+	require.Equal(t, []entity.CustomMetric{failed, backups}, agg.Last.Custom)
+}
+
+func TestAggregateWindow_Empty(t *testing.T) {
+	agg := AggregateWindow(nil)
+
+	require.Zero(t, agg.Samples)
+	require.Nil(t, agg.Avg)
+}
+
+func TestAggregateWindow_TopTalkers(t *testing.T) {
+	now := time.Now()
+	samples := []*entity.SystemMetrics{
+		{
+			Timestamp: now.Add(-2 * time.Second),
+			TopTalkers: []entity.TopTalker{
+				{Src: "a", Dst: "b", Protocol: "TCP", BPS: 900},
+				{Src: "c", Dst: "d", Protocol: "UDP", BPS: 300},
+			},
+		},
+		{
+			Timestamp: now.Add(-time.Second),
+			TopTalkers: []entity.TopTalker{
+				{Src: "e", Dst: "f", Protocol: "TCP", BPS: 1200},
+				{Src: "c", Dst: "d", Protocol: "UDP", BPS: 300},
+			},
+		},
+		{
+			Timestamp: now,
+		},
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.Equal(t, []entity.TopTalker{
+		{Src: "e", Dst: "f", Protocol: "TCP", BPS: 600},
+		{Src: "a", Dst: "b", Protocol: "TCP", BPS: 450},
+	}, agg.Avg.TopTalkers)
+	require.Equal(t, []entity.TopTalker{
+		{Src: "c", Dst: "d", Protocol: "UDP", BPS: 300},
+		{Src: "a", Dst: "b", Protocol: "TCP", BPS: 0},
+	}, agg.Min.TopTalkers)
+}
+
+func TestAggregateWindow_Interfaces(t *testing.T) {
+	now := time.Now()
+	samples := []*entity.SystemMetrics{
+		{Timestamp: now.Add(-time.Second), Interfaces: []entity.InterfaceStats{
+			{Name: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 100, TxDropsPerSec: 2},
+		}},
+		{Timestamp: now, Interfaces: []entity.InterfaceStats{
+			{Name: "eth0", State: "down", RxBytesPerSec: 300},
+			{Name: "eth1", State: "up", SpeedMbps: 10000, TxBytesPerSec: 50},
+		}},
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.Equal(t, []entity.InterfaceStats{
+		{Name: "eth0", State: "down", RxBytesPerSec: 200, TxDropsPerSec: 1},
+		{Name: "eth1", State: "up", SpeedMbps: 10000, TxBytesPerSec: 50},
+	}, agg.Avg.Interfaces)
+	require.Equal(t, 300.0, agg.Max.Interfaces[0].RxBytesPerSec)
+	require.Equal(t, samples[1].Interfaces, agg.Last.Interfaces)
+}
+
+func TestAggregateWindow_Pressure(t *testing.T) {
+	now := time.Now()
+	sample := func(at time.Time, percent, avg10 float64, total uint64) *entity.SystemMetrics {
+		return &entity.SystemMetrics{Timestamp: at, Pressure: []entity.PressureStats{
+			{Resource: "io", Some: entity.PressureLine{Percent: percent, Avg10: avg10, TotalUsec: total}},
+		}}
+	}
+	samples := []*entity.SystemMetrics{
+		sample(now.Add(-2*time.Second), 10, 4, 100000),
+		sample(now.Add(-time.Second), 30, 6, 400000),
+		sample(now, 20, 8, 600000),
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.Equal(t, []entity.PressureStats{
+		{Resource: "io", Some: entity.PressureLine{Percent: 20, Avg10: 6, TotalUsec: 600000}},
+	}, agg.Avg.Pressure)
+	require.Equal(t, 30.0, agg.Max.Pressure[0].Some.Percent)
+	require.Equal(t, 10.0, agg.Min.Pressure[0].Some.Percent)
+	require.Equal(t, samples[2].Pressure, agg.Last.Pressure)
+}
+
+func TestAggregateWindow_Cgroups(t *testing.T) {
+	now := time.Now()
+	samples := []*entity.SystemMetrics{
+		{Timestamp: now.Add(-time.Second), Cgroups: []entity.CgroupStats{
+			{Path: "/db.service", CPUPercent: 20, MemoryMB: 100, MemoryLimitMB: 512, Pids: 4},
+			{Path: "/web.service", CPUPercent: 10, MemoryMB: 50},
+		}},
+		{Timestamp: now, Cgroups: []entity.CgroupStats{
+			{Path: "/web.service", CPUPercent: 90, MemoryMB: 70},
+			{Path: "/db.service", CPUPercent: 30, MemoryMB: 200, MemoryLimitMB: 1024, Pids: 6},
+		}},
+	}
+
+	agg := AggregateWindow(samples)
+
+	require.Equal(t, []entity.CgroupStats{
+		{Path: "/web.service", CPUPercent: 50, MemoryMB: 60},
+		{Path: "/db.service", CPUPercent: 25, MemoryMB: 150, MemoryLimitMB: 1024, Pids: 5},
+	}, agg.Avg.Cgroups)
+	require.Equal(t, uint64(200), agg.Max.Cgroups[1].MemoryMB)
+	require.Equal(t, samples[1].Cgroups, agg.Last.Cgroups)
+}
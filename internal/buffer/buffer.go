@@ -1,6 +1,7 @@
 package buffer
 
 import (
+	"slices"
 	"sync"
 	"time"
 
@@ -8,18 +9,37 @@ import (
 )
 
 type ClientBuffer struct {
+	id            uint64
 	windowSeconds int
-	data          []*entity.SystemMetrics
-	mu            sync.Mutex
+	// collectors are the ones kept running for this subscriber.
+	collectors   []string
+	registeredAt time.Time
+	lastAccess   time.Time
+	data         []*entity.SystemMetrics
+	mu           sync.Mutex
 }
 
 func NewClientBuffer(windowSeconds int) *ClientBuffer {
+	now := time.Now()
 	return &ClientBuffer{
 		windowSeconds: windowSeconds,
+		registeredAt:  now,
+		lastAccess:    now,
 		data:          make([]*entity.SystemMetrics, 0),
 	}
 }
 
+func (b *ClientBuffer) ID() uint64 {
+	return b.id
+}
+
+func (b *ClientBuffer) WindowSeconds() int {
+	return b.windowSeconds
+}
+
+// Add appends a sample and drops the ones older than the window. The latest
+// sample of each collector is kept however old: a category collected less
+// often than the window spans would otherwise vanish between its readings.
 func (b *ClientBuffer) Add(metric *entity.SystemMetrics) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -28,13 +48,49 @@ func (b *ClientBuffer) Add(metric *entity.SystemMetrics) {
 	b.data = append(b.data, metric)
 
 	cutoff := now.Add(-time.Duration(b.windowSeconds) * time.Second)
-	for len(b.data) > 0 && b.data[0].Timestamp.Before(cutoff) {
-		b.data = b.data[1:]
+	latest := make(map[string]bool)
+	kept := make([]*entity.SystemMetrics, 0, len(b.data))
+	for idx := len(b.data) - 1; idx >= 0; idx-- {
+		m := b.data[idx]
+		source := sampleSource(m)
+		if !m.Timestamp.Before(cutoff) || (source != "" && !latest[source]) {
+			kept = append(kept, m)
+		}
+		latest[source] = true
+	}
+	slices.Reverse(kept)
+	b.data = kept
+}
+
+// sampleSource names the collector of a sample after its status, and after
+// its command for the custom metrics, which share a category.
+func sampleSource(m *entity.SystemMetrics) string {
+	if len(m.Statuses) == 0 {
+		return ""
+	}
+	source := string(m.Statuses[0].Category)
+	if len(m.Custom) > 0 {
+		source += ":" + m.Custom[0].Name
 	}
+	return source
 }
 
 func (b *ClientBuffer) Get() []*entity.SystemMetrics {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	return b.data
+	b.lastAccess = time.Now()
+
+	data := make([]*entity.SystemMetrics, len(b.data))
+	copy(data, b.data)
+	return data
+}
+
+func (b *ClientBuffer) Aggregate() Aggregate {
+	return AggregateWindow(b.Get())
+}
+
+func (b *ClientBuffer) LastAccess() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastAccess
 }
@@ -103,3 +103,29 @@ func TestClientBuffer_Add(t *testing.T) {
 		})
 	}
 }
+
+func TestClientBuffer_AddKeepsLatestOfCategory(t *testing.T) {
+	now := time.Now()
+	sample := func(category entity.Category, age time.Duration) *entity.SystemMetrics {
+		return &entity.SystemMetrics{
+			Timestamp: now.Add(-age),
+			Statuses:  []entity.CollectionStatus{{Category: category, State: entity.CollectionOK}},
+		}
+	}
+
+	queue := sample(entity.CategoryCustom, 30*time.Second)
+	queue.Custom = []entity.CustomMetric{{Name: "queue", State: entity.CollectionOK}}
+	backups := sample(entity.CategoryCustom, 10*time.Second)
+	backups.Custom = []entity.CustomMetric{{Name: "backups", State: entity.CollectionOK}}
+
+	buffer := NewClientBuffer(5)
+	fsOld := sample(entity.CategoryFilesystem, 20*time.Second)
+	fsLatest := sample(entity.CategoryFilesystem, 8*time.Second)
+	cpuOld := sample(entity.CategoryCPU, 6*time.Second)
+	cpuRecent := sample(entity.CategoryCPU, 2*time.Second)
+	buffer.data = append(buffer.data, queue, fsOld, backups, fsLatest, cpuOld, cpuRecent)
+
+	cpuNow := sample(entity.CategoryCPU, 0)
+	buffer.Add(cpuNow)
+	require.Equal(t, []*entity.SystemMetrics{queue, backups, fsLatest, cpuRecent, cpuNow}, buffer.Get())
+}
@@ -2,66 +2,339 @@ package buffer
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/dimryb/system-monitor/internal/collector"
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
 	i "github.com/dimryb/system-monitor/internal/interface"
 )
 
+const (
+	cleanupInterval = 10 * time.Second
+	abandonGrace    = 30 * time.Second
+)
+
+var ErrCategoryDisabled = errors.New("disabled in configuration")
+
+type SubscriberInfo struct {
+	ID            uint64
+	WindowSeconds int
+	RegisteredAt  time.Time
+	LastAccess    time.Time
+}
+
+// runner is a collector scheduled for the subscribers that need it.
+type runner struct {
+	users  int
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
 type GlobalCollector struct {
-	collector i.Collector
-	buffers   []*ClientBuffer
-	mu        sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
-	log       i.Logger
+	collectors  []collector.Scheduled
+	unavailable map[entity.Category]error
+	buffers     map[uint64]*ClientBuffer
+	runners     map[string]*runner
+	running     sync.WaitGroup
+	stopped     bool
+	nextID      uint64
+	mu          sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	log         i.Logger
+}
+
+func NewGlobalCollector(ctx context.Context, log i.Logger, cfg config.Collectors) (*GlobalCollector, error) {
+	all, err := collector.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newGlobalCollector(ctx, log, all), nil
 }
 
-func NewGlobalCollector(ctx context.Context, log i.Logger) *GlobalCollector {
+// newGlobalCollector keeps the collectors that are available on this host.
+func newGlobalCollector(ctx context.Context, log i.Logger, all []collector.Scheduled) *GlobalCollector {
+	var collectors []collector.Scheduled
+	unavailable := make(map[entity.Category]error)
+	for _, c := range all {
+		if err := c.Available(); err != nil {
+			log.Infof("Collector %s is unavailable: %v", c.Name(), err)
+			unavailable[c.Category()] = err
+			continue
+		}
+		collectors = append(collectors, c)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	return &GlobalCollector{
-		collector: collector.NewCollector(time.Second),
-		buffers:   make([]*ClientBuffer, 0),
-		ctx:       ctx,
-		cancel:    cancel,
-		log:       log,
+		collectors:  collectors,
+		unavailable: unavailable,
+		buffers:     make(map[uint64]*ClientBuffer),
+		runners:     make(map[string]*runner),
+		ctx:         ctx,
+		cancel:      cancel,
+		log:         log,
 	}
 }
 
-func (gc *GlobalCollector) Register(windowSeconds int) *ClientBuffer {
-	gc.mu.Lock()
-	defer gc.mu.Unlock()
+// Available returns why a category is not collected, or nil when it is.
+func (gc *GlobalCollector) Available(category entity.Category) error {
+	for _, c := range gc.collectors {
+		if c.Category() == category {
+			return nil
+		}
+	}
+	if err, ok := gc.unavailable[category]; ok {
+		return err
+	}
+	return ErrCategoryDisabled
+}
 
+// Unavailable returns the statuses of the enabled categories that cannot be
+// collected on this host at all, ordered by category.
+func (gc *GlobalCollector) Unavailable() []entity.CollectionStatus {
+	statuses := make([]entity.CollectionStatus, 0, len(gc.unavailable))
+	for category, err := range gc.unavailable {
+		statuses = append(statuses, entity.CollectionStatus{
+			Category: category,
+			State:    entity.CollectionUnavailable,
+			Message:  err.Error(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Category < statuses[j].Category
+	})
+	return statuses
+}
+
+// Register creates a buffer for a new subscriber and starts the collectors of
+// the given categories, or of every available category when none is given.
+// The buffer is removed automatically once ctx is done.
+func (gc *GlobalCollector) Register(ctx context.Context, windowSeconds int, categories []entity.Category) *ClientBuffer {
 	buffer := NewClientBuffer(windowSeconds)
-	gc.buffers = append(gc.buffers, buffer)
+	for _, c := range gc.collectors {
+		if len(categories) == 0 || slices.Contains(categories, c.Category()) {
+			buffer.collectors = append(buffer.collectors, c.Name())
+		}
+	}
+
+	gc.mu.Lock()
+	gc.nextID++
+	buffer.id = gc.nextID
+	gc.buffers[buffer.id] = buffer
+	for _, name := range buffer.collectors {
+		gc.acquire(name)
+	}
+	total := len(gc.buffers)
+	gc.mu.Unlock()
+
+	gc.log.Debugf("Subscriber %d registered: window=%ds, collectors=%v, active=%d",
+		buffer.id, windowSeconds, buffer.collectors, total)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			gc.Unregister(buffer.id)
+		case <-gc.ctx.Done():
+		}
+	}()
+
 	return buffer
 }
 
+func (gc *GlobalCollector) Unregister(id uint64) {
+	gc.mu.Lock()
+	buffer, ok := gc.buffers[id]
+	if ok {
+		gc.remove(buffer)
+	}
+	total := len(gc.buffers)
+	gc.mu.Unlock()
+
+	if ok {
+		gc.log.Debugf("Subscriber %d unregistered: active=%d", id, total)
+	}
+}
+
+// remove drops a buffer and stops the collectors nobody else needs. The
+// caller holds gc.mu.
+func (gc *GlobalCollector) remove(buffer *ClientBuffer) {
+	delete(gc.buffers, buffer.id)
+	for _, name := range buffer.collectors {
+		gc.release(name)
+	}
+}
+
+// acquire starts a collector for its first user. A collector that is still
+// stopping is started again once it has stopped, so it never runs twice at
+// the same time. The caller holds gc.mu.
+func (gc *GlobalCollector) acquire(name string) {
+	r := gc.runners[name]
+	if r != nil && r.users > 0 {
+		r.users++
+		return
+	}
+	// Start is waiting for the runners already.
+	if gc.stopped {
+		return
+	}
+
+	var c collector.Scheduled
+	for _, candidate := range gc.collectors {
+		if candidate.Name() == name {
+			c = candidate
+		}
+	}
+
+	ctx, cancel := context.WithCancel(gc.ctx)
+	next := &runner{users: 1, cancel: cancel, done: make(chan struct{})}
+	gc.runners[name] = next
+
+	gc.running.Add(1)
+	go func() {
+		defer gc.running.Done()
+		defer close(next.done)
+		if r != nil {
+			<-r.done
+		}
+		gc.run(ctx, c)
+	}()
+}
+
+// release stops a collector after its last user is gone. The caller holds gc.mu.
+func (gc *GlobalCollector) release(name string) {
+	r := gc.runners[name]
+	if r == nil || r.users == 0 {
+		return
+	}
+	r.users--
+	if r.users == 0 {
+		r.cancel()
+	}
+}
+
+func (gc *GlobalCollector) Subscribers() []SubscriberInfo {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+
+	subscribers := make([]SubscriberInfo, 0, len(gc.buffers))
+	for id, b := range gc.buffers {
+		subscribers = append(subscribers, SubscriberInfo{
+			ID:            id,
+			WindowSeconds: b.windowSeconds,
+			RegisteredAt:  b.registeredAt,
+			LastAccess:    b.LastAccess(),
+		})
+	}
+	sort.Slice(subscribers, func(i, j int) bool {
+		return subscribers[i].ID < subscribers[j].ID
+	})
+	return subscribers
+}
+
+// Start removes abandoned subscribers until Stop is called. Collectors only
+// run while a subscriber needs them, each at its own period, so an idle
+// daemon collects nothing. A sample holds only the part of the snapshot its
+// collector fills.
 func (gc *GlobalCollector) Start() {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
 
 	for {
 		select {
 		case <-gc.ctx.Done():
+			gc.mu.Lock()
+			gc.stopped = true
+			gc.mu.Unlock()
+			gc.running.Wait()
+			return
+		case now := <-cleanup.C:
+			gc.removeAbandoned(now)
+		}
+	}
+}
+
+// run collects until ctx is done. The first reading of a collector reporting
+// rates is not published, it only sets the baseline.
+func (gc *GlobalCollector) run(ctx context.Context, c collector.Scheduled) {
+	gc.log.Debugf("Collector %s started: period=%s", c.Name(), c.Period)
+	defer func() {
+		if s, ok := c.Collector.(i.Stopper); ok {
+			s.Stop()
+		}
+		gc.log.Debugf("Collector %s stopped", c.Name())
+	}()
+
+	if _, ok := c.Collector.(i.RateCollector); ok {
+		gc.collect(ctx, c)
+	} else {
+		gc.publish(gc.collect(ctx, c))
+	}
+
+	ticker := time.NewTicker(c.Period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			metric, err := gc.collector.Collect(gc.ctx)
-			if err != nil {
-				gc.log.Errorf("failed to collect metric: %v", err)
-				continue
-			}
-
-			gc.mu.RLock()
-			for _, b := range gc.buffers {
-				b.Add(metric)
-			}
-			gc.mu.RUnlock()
+			gc.publish(gc.collect(ctx, c))
+		}
+	}
+}
+
+// collect runs a collector, bounded by its period. The sample always carries
+// the status of the category; when the collector fails it holds nothing else.
+func (gc *GlobalCollector) collect(ctx context.Context, c collector.Scheduled) *entity.SystemMetrics {
+	ctx, cancel := context.WithTimeout(ctx, c.Period)
+	defer cancel()
+
+	now := time.Now()
+	m := &entity.SystemMetrics{Timestamp: now}
+	status := entity.CollectionStatus{Category: c.Category(), State: entity.CollectionOK}
+	if err := c.Collect(ctx, m); err != nil {
+		status.State, status.Message = entity.CollectionError, err.Error()
+		if errors.Is(err, collector.ErrUnavailable) {
+			status.State = entity.CollectionUnavailable
+			gc.log.Debugf("Collector %s is unavailable: %v", c.Name(), err)
+		} else {
+			gc.log.Errorf("failed to collect %s: %v", c.Name(), err)
 		}
+		m = &entity.SystemMetrics{Timestamp: now}
+	}
+	m.Statuses = []entity.CollectionStatus{status}
+	return m
+}
+
+func (gc *GlobalCollector) publish(m *entity.SystemMetrics) {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	for _, b := range gc.buffers {
+		b.Add(m)
 	}
 }
 
 func (gc *GlobalCollector) Stop() {
 	gc.cancel()
 }
+
+// removeAbandoned drops buffers that nobody has read for longer than twice
+// their window, e.g. when a subscriber was registered without a cancelable context.
+func (gc *GlobalCollector) removeAbandoned(now time.Time) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	for id, b := range gc.buffers {
+		ttl := 2*time.Duration(b.windowSeconds)*time.Second + abandonGrace
+		if now.Sub(b.LastAccess()) > ttl {
+			gc.remove(b)
+			gc.log.Warnf("Subscriber %d abandoned, buffer removed: active=%d", id, len(gc.buffers))
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/collector"
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)  {}
+func (nopLogger) Info(string, ...any)   {}
+func (nopLogger) Warn(string, ...any)   {}
+func (nopLogger) Error(string, ...any)  {}
+func (nopLogger) Fatal(string, ...any)  {}
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Infof(string, ...any)  {}
+func (nopLogger) Warnf(string, ...any)  {}
+func (nopLogger) Errorf(string, ...any) {}
+func (nopLogger) Fatalf(string, ...any) {}
+
+func TestGlobalCollector_SubscriberLifecycle(t *testing.T) {
+	gc, err := NewGlobalCollector(context.Background(), nopLogger{}, config.Collectors{})
+	require.NoError(t, err)
+	defer gc.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := gc.Register(ctx, 10, nil)
+	second := gc.Register(context.Background(), 5, nil)
+
+	require.NotEqual(t, first.ID(), second.ID())
+	require.Len(t, gc.Subscribers(), 2)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return len(gc.Subscribers()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, second.ID(), gc.Subscribers()[0].ID)
+
+	gc.Unregister(second.ID())
+	gc.Unregister(second.ID())
+	require.Empty(t, gc.Subscribers())
+}
+
+func TestGlobalCollector_RemoveAbandoned(t *testing.T) {
+	gc, err := NewGlobalCollector(context.Background(), nopLogger{}, config.Collectors{})
+	require.NoError(t, err)
+	defer gc.Stop()
+
+	active := gc.Register(context.Background(), 5, nil)
+	abandoned := gc.Register(context.Background(), 5, nil)
+
+	now := time.Now().Add(2*5*time.Second + abandonGrace + time.Second)
+	active.lastAccess = now
+
+	gc.removeAbandoned(now)
+
+	subscribers := gc.Subscribers()
+	require.Len(t, subscribers, 1)
+	require.Equal(t, active.ID(), subscribers[0].ID)
+	require.NotEqual(t, abandoned.ID(), subscribers[0].ID)
+}
+
+type stubCollector struct {
+	name        string
+	unavailable error
+	err         error
+	collect     func(m *entity.SystemMetrics)
+
+	mu    sync.Mutex
+	calls int
+	stops int
+}
+
+func (s *stubCollector) Name() string     { return s.name }
+func (s *stubCollector) Available() error { return s.unavailable }
+
+func (s *stubCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	if s.collect != nil {
+		s.collect(m)
+	}
+	return s.err
+}
+
+func (s *stubCollector) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops++
+}
+
+func (s *stubCollector) state() (calls, stops int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls, s.stops
+}
+
+// rateStub is a collector reporting rates, whose first reading is a baseline.
+type rateStub struct {
+	*stubCollector
+}
+
+func (rateStub) ReportsRates() {}
+
+func TestGlobalCollector_Collect(t *testing.T) {
+	load := &stubCollector{name: "loadavg", collect: func(m *entity.SystemMetrics) {
+		m.LoadAverage = &entity.LoadAverage{OneMin: 1}
+	}}
+	broken := &stubCollector{name: "disk", err: errors.New("no such file"), collect: func(m *entity.SystemMetrics) {
+		m.Disks = []entity.DiskStats{{Device: "sda"}}
+	}}
+	denied := &stubCollector{name: "talkers", err: fmt.Errorf("%w: no CAP_NET_RAW", collector.ErrUnavailable)}
+	missing := &stubCollector{name: "protocols", unavailable: errors.New("no such file")}
+
+	gc := newGlobalCollector(context.Background(), nopLogger{}, []collector.Scheduled{
+		{Collector: load, Period: time.Second},
+		{Collector: broken, Period: time.Second},
+		{Collector: denied, Period: time.Second},
+		{Collector: missing, Period: time.Second},
+	})
+	defer gc.Stop()
+	require.Len(t, gc.collectors, 3)
+
+	m := gc.collect(context.Background(), gc.collectors[0])
+	require.Equal(t, &entity.LoadAverage{OneMin: 1}, m.LoadAverage)
+	require.Equal(t, []entity.CollectionStatus{
+		{Category: entity.CategoryLoadAverage, State: entity.CollectionOK},
+	}, m.Statuses)
+
+	m = gc.collect(context.Background(), gc.collectors[1])
+	require.Nil(t, m.Disks)
+	require.Equal(t, []entity.CollectionStatus{
+		{Category: entity.CategoryDisk, State: entity.CollectionError, Message: "no such file"},
+	}, m.Statuses)
+
+	m = gc.collect(context.Background(), gc.collectors[2])
+	require.Equal(t, []entity.CollectionStatus{
+		{Category: entity.CategoryTalkers, State: entity.CollectionUnavailable, Message: "unavailable on this host: no CAP_NET_RAW"},
+	}, m.Statuses)
+
+	require.Equal(t, []entity.CollectionStatus{
+		{Category: entity.CategoryProtocols, State: entity.CollectionUnavailable, Message: "no such file"},
+	}, gc.Unavailable())
+}
+
+func TestGlobalCollector_Periods(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	stub := func(name string) *stubCollector {
+		return &stubCollector{name: name, collect: func(m *entity.SystemMetrics) {
+			mu.Lock()
+			counts[name]++
+			mu.Unlock()
+			m.LoadAverage = &entity.LoadAverage{}
+		}}
+	}
+
+	gc := newGlobalCollector(context.Background(), nopLogger{}, []collector.Scheduled{
+		{Collector: stub("fast"), Period: 10 * time.Millisecond},
+		{Collector: stub("slow"), Period: 100 * time.Millisecond},
+	})
+	buf := gc.Register(context.Background(), 10, nil)
+
+	done := make(chan struct{})
+	go func() {
+		gc.Start()
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return counts["slow"] >= 2
+	}, 2*time.Second, 10*time.Millisecond)
+	gc.Stop()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Greater(t, counts["fast"], 3*counts["slow"])
+	require.Len(t, buf.Get(), counts["fast"]+counts["slow"])
+}
+
+func TestGlobalCollector_FirstReading(t *testing.T) {
+	load := &stubCollector{name: "loadavg", collect: func(m *entity.SystemMetrics) {
+		m.LoadAverage = &entity.LoadAverage{OneMin: 1}
+	}}
+	cpu := &stubCollector{name: "cpu", collect: func(m *entity.SystemMetrics) {
+		m.CPUUsagePercent = 50
+	}}
+
+	gc := newGlobalCollector(context.Background(), nopLogger{}, []collector.Scheduled{
+		{Collector: load, Period: time.Hour},
+		{Collector: rateStub{cpu}, Period: time.Hour},
+	})
+	defer gc.Stop()
+	buf := gc.Register(context.Background(), 10, nil)
+
+	// The state is published at once, the rates wait for the next period.
+	require.Eventually(t, func() bool {
+		calls, _ := cpu.state()
+		return calls == 1 && len(buf.Get()) == 1
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	samples := buf.Get()
+	require.Len(t, samples, 1)
+	require.Equal(t, &entity.LoadAverage{OneMin: 1}, samples[0].LoadAverage)
+}
+
+func TestGlobalCollector_Available(t *testing.T) {
+	gc := newGlobalCollector(context.Background(), nopLogger{}, []collector.Scheduled{
+		{Collector: &stubCollector{name: "cpu"}, Period: time.Second},
+		{Collector: &stubCollector{name: "talkers", unavailable: errors.New("capture is disabled")}, Period: time.Second},
+	})
+	defer gc.Stop()
+
+	require.NoError(t, gc.Available(entity.CategoryCPU))
+	require.EqualError(t, gc.Available(entity.CategoryTalkers), "capture is disabled")
+	require.ErrorIs(t, gc.Available(entity.CategoryDisk), ErrCategoryDisabled)
+}
+
+func TestGlobalCollector_Lazy(t *testing.T) {
+	load := &stubCollector{name: "loadavg", collect: func(m *entity.SystemMetrics) {
+		m.LoadAverage = &entity.LoadAverage{}
+	}}
+	cpu := &stubCollector{name: "cpu"}
+	cpu.collect = func(m *entity.SystemMetrics) {
+		calls, _ := cpu.state()
+		m.CPUUsagePercent = float64(calls)
+	}
+
+	gc := newGlobalCollector(context.Background(), nopLogger{}, []collector.Scheduled{
+		{Collector: load, Period: 10 * time.Millisecond},
+		{Collector: rateStub{cpu}, Period: 10 * time.Millisecond},
+	})
+	done := make(chan struct{})
+	go func() {
+		gc.Start()
+		close(done)
+	}()
+	defer func() {
+		gc.Stop()
+		<-done
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	calls, _ := load.state()
+	require.Zero(t, calls, "nothing is collected without subscribers")
+
+	first := gc.Register(context.Background(), 10, []entity.Category{entity.CategoryLoadAverage})
+	second := gc.Register(context.Background(), 10, []entity.Category{entity.CategoryLoadAverage})
+	require.Eventually(t, func() bool {
+		return len(first.Get()) >= 2
+	}, time.Second, 10*time.Millisecond)
+	calls, _ = cpu.state()
+	require.Zero(t, calls)
+	for _, m := range first.Get() {
+		require.NotNil(t, m.LoadAverage)
+	}
+
+	gc.Unregister(first.ID())
+	_, stops := load.state()
+	require.Zero(t, stops, "the collector is still needed")
+
+	gc.Unregister(second.ID())
+	require.Eventually(t, func() bool {
+		_, stops := load.state()
+		return stops == 1
+	}, time.Second, 10*time.Millisecond)
+	stopped, _ := load.state()
+	time.Sleep(50 * time.Millisecond)
+	calls, _ = load.state()
+	require.Equal(t, stopped, calls)
+
+	// Starting again takes a new baseline before publishing.
+	third := gc.Register(context.Background(), 10, nil)
+	require.Eventually(t, func() bool {
+		calls, _ := cpu.state()
+		return calls >= 2 && len(third.Get()) >= 2
+	}, time.Second, 10*time.Millisecond)
+	for _, m := range third.Get() {
+		if m.LoadAverage == nil {
+			require.Greater(t, m.CPUUsagePercent, float64(1))
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package capture
+
+func OpenLive(string) (Source, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,151 @@
+package capture
+
+import (
+	"container/heap"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+)
+
+type FlowKey struct {
+	Transport Transport
+	Src       netip.AddrPort
+	Dst       netip.AddrPort
+}
+
+type Flow struct {
+	FlowKey
+	Bytes uint64
+}
+
+type flowEntry struct {
+	key   FlowKey
+	bytes uint64
+	index int
+}
+
+// FlowTable accumulates bytes per src→dst flow. Its size is bounded: when it
+// is full, a new flow replaces the smallest one and inherits its counter
+// (the Space-Saving algorithm), so heavy flows are never lost under churn and
+// their counters are overestimated by at most the evicted value.
+type FlowTable struct {
+	mu       sync.Mutex
+	maxFlows int
+	flows    map[FlowKey]*flowEntry
+	heap     flowHeap
+	// first and last are the capture timestamps of the packets observed
+	// since the previous drain.
+	first time.Time
+	last  time.Time
+}
+
+func NewFlowTable(maxFlows int) *FlowTable {
+	if maxFlows <= 0 {
+		maxFlows = 1
+	}
+	return &FlowTable{
+		maxFlows: maxFlows,
+		flows:    make(map[FlowKey]*flowEntry, maxFlows),
+	}
+}
+
+// Observe accounts an IP packet. Non-IP packets only count for the time
+// covered by the capture.
+func (t *FlowTable) Observe(p *Packet) {
+	t.mu.Lock()
+	if t.first.IsZero() || p.Timestamp.Before(t.first) {
+		t.first = p.Timestamp
+	}
+	if p.Timestamp.After(t.last) {
+		t.last = p.Timestamp
+	}
+	t.mu.Unlock()
+
+	if !p.Src.IsValid() || !p.Dst.IsValid() {
+		return
+	}
+	t.Add(FlowKey{
+		Transport: p.Transport,
+		Src:       netip.AddrPortFrom(p.Src, p.SrcPort),
+		Dst:       netip.AddrPortFrom(p.Dst, p.DstPort),
+	}, uint64(p.Length)) //nolint:gosec
+}
+
+func (t *FlowTable) Add(key FlowKey, bytes uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if e, ok := t.flows[key]; ok {
+		e.bytes += bytes
+		heap.Fix(&t.heap, e.index)
+		return
+	}
+
+	if len(t.flows) < t.maxFlows {
+		e := &flowEntry{key: key, bytes: bytes}
+		t.flows[key] = e
+		heap.Push(&t.heap, e)
+		return
+	}
+
+	smallest := t.heap[0]
+	delete(t.flows, smallest.key)
+	smallest.key = key
+	smallest.bytes += bytes
+	t.flows[key] = smallest
+	heap.Fix(&t.heap, 0)
+}
+
+// Drain returns all flows sorted by bytes, largest first, and the capture
+// timestamps of the first and the last packet observed since the previous
+// call, zero when there was none. It resets the table.
+func (t *FlowTable) Drain() (flows []Flow, first, last time.Time) {
+	t.mu.Lock()
+	entries := t.heap
+	first, last = t.first, t.last
+	t.flows = make(map[FlowKey]*flowEntry, t.maxFlows)
+	t.heap = nil
+	t.first, t.last = time.Time{}, time.Time{}
+	t.mu.Unlock()
+
+	flows = make([]Flow, 0, len(entries))
+	for _, e := range entries {
+		flows = append(flows, Flow{FlowKey: e.key, Bytes: e.bytes})
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		return flows[i].Bytes > flows[j].Bytes
+	})
+	return flows, first, last
+}
+
+func (t *FlowTable) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.flows)
+}
+
+// flowHeap is a min-heap of flows by bytes.
+type flowHeap []*flowEntry
+
+func (h flowHeap) Len() int           { return len(h) }
+func (h flowHeap) Less(i, j int) bool { return h[i].bytes < h[j].bytes }
+
+func (h flowHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *flowHeap) Push(x any) {
+	e := x.(*flowEntry) //nolint:forcetypeassert
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *flowHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func flowKey(src string, port uint16) FlowKey {
+	return FlowKey{
+		Transport: TransportTCP,
+		Src:       netip.AddrPortFrom(netip.MustParseAddr(src), port),
+		Dst:       netip.MustParseAddrPort("10.0.0.100:443"),
+	}
+}
+
+func TestFlowTable(t *testing.T) {
+	table := NewFlowTable(10)
+	table.Add(flowKey("10.0.0.1", 1000), 100)
+	table.Add(flowKey("10.0.0.2", 1000), 300)
+	table.Add(flowKey("10.0.0.1", 1000), 250)
+
+	flows, _, _ := table.Drain()
+	require.Equal(t, []Flow{
+		{FlowKey: flowKey("10.0.0.1", 1000), Bytes: 350},
+		{FlowKey: flowKey("10.0.0.2", 1000), Bytes: 300},
+	}, flows)
+	require.Zero(t, table.Len())
+}
+
+func TestFlowTable_BoundedUnderChurn(t *testing.T) {
+	table := NewFlowTable(16)
+	heavy := flowKey("10.0.0.1", 1)
+
+	for i := 0; i < 10000; i++ {
+		table.Add(flowKey("192.168.0.1", uint16(1024+i)), 10)
+		if i%10 == 0 {
+			table.Add(heavy, 1000)
+		}
+		require.LessOrEqual(t, table.Len(), 16)
+	}
+
+	flows, _, _ := table.Drain()
+	require.Len(t, flows, 16)
+	require.Equal(t, heavy, flows[0].FlowKey)
+	require.GreaterOrEqual(t, flows[0].Bytes, uint64(1000*1000))
+}
+
+func TestFlowTable_Observe(t *testing.T) {
+	table := NewFlowTable(10)
+	table.Observe(&Packet{
+		Length: 1500, Transport: TransportUDP,
+		Src: netip.MustParseAddr("10.0.0.1"), SrcPort: 53,
+		Dst: netip.MustParseAddr("10.0.0.2"), DstPort: 40000,
+	})
+	table.Observe(&Packet{Length: 60, Network: NetworkARP})
+
+	flows, _, _ := table.Drain()
+	require.Len(t, flows, 1)
+	require.Equal(t, uint64(1500), flows[0].Bytes)
+	require.Equal(t, "10.0.0.1:53", flows[0].Src.String())
+}
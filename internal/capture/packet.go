@@ -0,0 +1,235 @@
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// LinkType identifies the framing of captured data. The values match the
+// LINKTYPE_* constants of the pcap file format.
+type LinkType uint32
+
+const (
+	LinkTypeEthernet LinkType = 1
+	LinkTypeRaw      LinkType = 101
+	LinkTypeLinuxSLL LinkType = 113
+	LinkTypeIPv4     LinkType = 228
+	LinkTypeIPv6     LinkType = 229
+	LinkTypeSLL2     LinkType = 276
+)
+
+type Network string
+
+const (
+	NetworkIPv4  Network = "IPv4"
+	NetworkIPv6  Network = "IPv6"
+	NetworkARP   Network = "ARP"
+	NetworkOther Network = "other"
+)
+
+type Transport string
+
+const (
+	TransportNone   Transport = ""
+	TransportTCP    Transport = "TCP"
+	TransportUDP    Transport = "UDP"
+	TransportICMP   Transport = "ICMP"
+	TransportICMPv6 Transport = "ICMPv6"
+	TransportOther  Transport = "other"
+)
+
+const (
+	etherTypeIPv4  = 0x0800
+	etherTypeARP   = 0x0806
+	etherTypeVLAN  = 0x8100
+	etherTypeQinQ  = 0x88A8
+	etherTypeQinQ2 = 0x9100
+	etherTypeIPv6  = 0x86DD
+
+	ipProtoHopByHop = 0
+	ipProtoICMP     = 1
+	ipProtoTCP      = 6
+	ipProtoUDP      = 17
+	ipProtoRouting  = 43
+	ipProtoFragment = 44
+	ipProtoAH       = 51
+	ipProtoICMPv6   = 58
+	ipProtoNoNext   = 59
+	ipProtoDstOpts  = 60
+)
+
+var ErrTruncated = errors.New("truncated packet")
+
+// Packet is the decoded summary of a captured frame.
+type Packet struct {
+	Timestamp time.Time
+	// Length is the original length of the frame on the wire, which may be
+	// larger than the captured data.
+	Length int
+
+	Network   Network
+	Transport Transport
+	Src       netip.Addr
+	Dst       netip.Addr
+	SrcPort   uint16
+	DstPort   uint16
+}
+
+// Decode parses the headers of a frame. On error the fields decoded so far
+// are kept, so the packet can still be accounted for.
+func Decode(linkType LinkType, data []byte, p *Packet) error {
+	*p = Packet{Timestamp: p.Timestamp, Length: p.Length, Network: NetworkOther}
+
+	switch linkType {
+	case LinkTypeEthernet:
+		return decodeEthernet(data, p)
+	case LinkTypeLinuxSLL:
+		// Linux "cooked" capture, written by tcpdump -i any.
+		if len(data) < 16 {
+			return ErrTruncated
+		}
+		return decodeEtherType(binary.BigEndian.Uint16(data[14:16]), data[16:], p)
+	case LinkTypeSLL2:
+		if len(data) < 20 {
+			return ErrTruncated
+		}
+		return decodeEtherType(binary.BigEndian.Uint16(data[0:2]), data[20:], p)
+	case LinkTypeRaw, LinkTypeIPv4, LinkTypeIPv6:
+		if len(data) == 0 {
+			return ErrTruncated
+		}
+		switch data[0] >> 4 {
+		case 4:
+			return decodeIPv4(data, p)
+		case 6:
+			return decodeIPv6(data, p)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported link type %d", linkType)
+	}
+}
+
+func decodeEthernet(data []byte, p *Packet) error {
+	if len(data) < 14 {
+		return ErrTruncated
+	}
+	etherType := binary.BigEndian.Uint16(data[12:14])
+	data = data[14:]
+
+	for etherType == etherTypeVLAN || etherType == etherTypeQinQ || etherType == etherTypeQinQ2 {
+		if len(data) < 4 {
+			return ErrTruncated
+		}
+		etherType = binary.BigEndian.Uint16(data[2:4])
+		data = data[4:]
+	}
+	return decodeEtherType(etherType, data, p)
+}
+
+func decodeEtherType(etherType uint16, data []byte, p *Packet) error {
+	switch etherType {
+	case etherTypeIPv4:
+		return decodeIPv4(data, p)
+	case etherTypeIPv6:
+		return decodeIPv6(data, p)
+	case etherTypeARP:
+		p.Network = NetworkARP
+	}
+	return nil
+}
+
+func decodeIPv4(data []byte, p *Packet) error {
+	p.Network = NetworkIPv4
+	if len(data) < 20 {
+		return ErrTruncated
+	}
+	headerLen := int(data[0]&0x0F) * 4
+	if headerLen < 20 || len(data) < headerLen {
+		return ErrTruncated
+	}
+
+	p.Src = netip.AddrFrom4([4]byte(data[12:16]))
+	p.Dst = netip.AddrFrom4([4]byte(data[16:20]))
+
+	// Only the first fragment carries the transport header.
+	firstFragment := binary.BigEndian.Uint16(data[6:8])&0x1FFF == 0
+	return decodeTransport(data[9], data[headerLen:], firstFragment, p)
+}
+
+func decodeIPv6(data []byte, p *Packet) error {
+	p.Network = NetworkIPv6
+	if len(data) < 40 {
+		return ErrTruncated
+	}
+
+	p.Src = netip.AddrFrom16([16]byte(data[8:24]))
+	p.Dst = netip.AddrFrom16([16]byte(data[24:40]))
+
+	next := data[6]
+	data = data[40:]
+	firstFragment := true
+	for {
+		switch next {
+		case ipProtoHopByHop, ipProtoRouting, ipProtoDstOpts:
+			if len(data) < 2 {
+				return ErrTruncated
+			}
+			size := (int(data[1]) + 1) * 8
+			if len(data) < size {
+				return ErrTruncated
+			}
+			next, data = data[0], data[size:]
+		case ipProtoFragment:
+			if len(data) < 8 {
+				return ErrTruncated
+			}
+			firstFragment = binary.BigEndian.Uint16(data[2:4])&0xFFF8 == 0
+			next, data = data[0], data[8:]
+		case ipProtoAH:
+			if len(data) < 2 {
+				return ErrTruncated
+			}
+			size := (int(data[1]) + 2) * 4
+			if len(data) < size {
+				return ErrTruncated
+			}
+			next, data = data[0], data[size:]
+		case ipProtoNoNext:
+			return nil
+		default:
+			return decodeTransport(next, data, firstFragment, p)
+		}
+	}
+}
+
+func decodeTransport(proto byte, data []byte, withHeader bool, p *Packet) error {
+	switch proto {
+	case ipProtoTCP:
+		p.Transport = TransportTCP
+	case ipProtoUDP:
+		p.Transport = TransportUDP
+	case ipProtoICMP:
+		p.Transport = TransportICMP
+		return nil
+	case ipProtoICMPv6:
+		p.Transport = TransportICMPv6
+		return nil
+	default:
+		p.Transport = TransportOther
+		return nil
+	}
+
+	if !withHeader {
+		return nil
+	}
+	if len(data) < 4 {
+		return ErrTruncated
+	}
+	p.SrcPort = binary.BigEndian.Uint16(data[0:2])
+	p.DstPort = binary.BigEndian.Uint16(data[2:4])
+	return nil
+}
@@ -0,0 +1,169 @@
+package capture
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ethernet(etherType uint16, payload []byte, vlans ...uint16) []byte {
+	frame := make([]byte, 12, 14+4*len(vlans)+len(payload))
+	for _, vlan := range vlans {
+		frame = binary.BigEndian.AppendUint16(frame, etherTypeVLAN)
+		frame = binary.BigEndian.AppendUint16(frame, vlan)
+	}
+	frame = binary.BigEndian.AppendUint16(frame, etherType)
+	return append(frame, payload...)
+}
+
+func ipv4(proto byte, src, dst string, fragOffset uint16, payload []byte) []byte {
+	header := make([]byte, 20)
+	header[0] = 0x45
+	binary.BigEndian.PutUint16(header[2:4], uint16(20+len(payload)))
+	binary.BigEndian.PutUint16(header[6:8], fragOffset)
+	header[8] = 64
+	header[9] = proto
+	s, d := netip.MustParseAddr(src).As4(), netip.MustParseAddr(dst).As4()
+	copy(header[12:16], s[:])
+	copy(header[16:20], d[:])
+	return append(header, payload...)
+}
+
+func ipv6(next byte, src, dst string, payload []byte) []byte {
+	header := make([]byte, 40)
+	header[0] = 0x60
+	binary.BigEndian.PutUint16(header[4:6], uint16(len(payload)))
+	header[6] = next
+	header[7] = 64
+	s, d := netip.MustParseAddr(src).As16(), netip.MustParseAddr(dst).As16()
+	copy(header[8:24], s[:])
+	copy(header[24:40], d[:])
+	return append(header, payload...)
+}
+
+func ports(src, dst uint16) []byte {
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint16(b[0:2], src)
+	binary.BigEndian.PutUint16(b[2:4], dst)
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkType LinkType
+		data     []byte
+		expected Packet
+		wantErr  bool
+	}{
+		{
+			name:     "IPv4 TCP over Ethernet",
+			linkType: LinkTypeEthernet,
+			data:     ethernet(etherTypeIPv4, ipv4(ipProtoTCP, "10.0.0.1", "10.0.0.2", 0, ports(443, 51234))),
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportTCP,
+				Src: netip.MustParseAddr("10.0.0.1"), Dst: netip.MustParseAddr("10.0.0.2"),
+				SrcPort: 443, DstPort: 51234,
+			},
+		},
+		{
+			name:     "IPv4 UDP over QinQ",
+			linkType: LinkTypeEthernet,
+			data:     ethernet(etherTypeIPv4, ipv4(ipProtoUDP, "192.168.1.1", "8.8.8.8", 0, ports(5353, 53)), 100, 200),
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportUDP,
+				Src: netip.MustParseAddr("192.168.1.1"), Dst: netip.MustParseAddr("8.8.8.8"),
+				SrcPort: 5353, DstPort: 53,
+			},
+		},
+		{
+			name:     "IPv4 non-first fragment has no ports",
+			linkType: LinkTypeEthernet,
+			data:     ethernet(etherTypeIPv4, ipv4(ipProtoUDP, "10.0.0.1", "10.0.0.2", 185, ports(1, 2))),
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportUDP,
+				Src: netip.MustParseAddr("10.0.0.1"), Dst: netip.MustParseAddr("10.0.0.2"),
+			},
+		},
+		{
+			name:     "IPv4 UDP over Linux cooked capture",
+			linkType: LinkTypeLinuxSLL,
+			data: append(
+				[]byte{0, 4, 0, 1, 0, 6, 1, 2, 3, 4, 5, 6, 0, 0, 0x08, 0x00},
+				ipv4(ipProtoUDP, "10.0.0.1", "10.0.0.2", 0, ports(5353, 53))...),
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportUDP,
+				Src: netip.MustParseAddr("10.0.0.1"), Dst: netip.MustParseAddr("10.0.0.2"),
+				SrcPort: 5353, DstPort: 53,
+			},
+		},
+		{
+			name:     "IPv4 ICMP",
+			linkType: LinkTypeRaw,
+			data:     ipv4(ipProtoICMP, "10.0.0.1", "10.0.0.2", 0, make([]byte, 8)),
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportICMP,
+				Src: netip.MustParseAddr("10.0.0.1"), Dst: netip.MustParseAddr("10.0.0.2"),
+			},
+		},
+		{
+			name:     "IPv6 UDP after hop-by-hop and fragment headers",
+			linkType: LinkTypeEthernet,
+			data: ethernet(etherTypeIPv6, ipv6(ipProtoHopByHop, "2001:db8::1", "2001:db8::2",
+				append(append([]byte{ipProtoFragment, 0, 0, 0, 0, 0, 0, 0},
+					ipProtoUDP, 0, 0, 0, 0, 0, 0, 1), ports(546, 547)...))),
+			expected: Packet{
+				Network: NetworkIPv6, Transport: TransportUDP,
+				Src: netip.MustParseAddr("2001:db8::1"), Dst: netip.MustParseAddr("2001:db8::2"),
+				SrcPort: 546, DstPort: 547,
+			},
+		},
+		{
+			name:     "IPv6 ICMPv6",
+			linkType: LinkTypeRaw,
+			data:     ipv6(ipProtoICMPv6, "fe80::1", "ff02::1", make([]byte, 8)),
+			expected: Packet{
+				Network: NetworkIPv6, Transport: TransportICMPv6,
+				Src: netip.MustParseAddr("fe80::1"), Dst: netip.MustParseAddr("ff02::1"),
+			},
+		},
+		{
+			name:     "ARP",
+			linkType: LinkTypeEthernet,
+			data:     ethernet(etherTypeARP, make([]byte, 28)),
+			expected: Packet{Network: NetworkARP},
+		},
+		{
+			name:     "Truncated TCP header",
+			linkType: LinkTypeEthernet,
+			data:     ethernet(etherTypeIPv4, ipv4(ipProtoTCP, "10.0.0.1", "10.0.0.2", 0, []byte{1, 187}))[:36],
+			expected: Packet{
+				Network: NetworkIPv4, Transport: TransportTCP,
+				Src: netip.MustParseAddr("10.0.0.1"), Dst: netip.MustParseAddr("10.0.0.2"),
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Truncated Ethernet header",
+			linkType: LinkTypeEthernet,
+			data:     make([]byte, 10),
+			expected: Packet{Network: NetworkOther},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Packet
+			err := Decode(tt.linkType, tt.data, &p)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expected, p)
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"time"
+)
+
+// ReplayMode tells how fast a capture file is replayed.
+type ReplayMode string
+
+const (
+	// ReplayRealtime delivers packets with the gaps they were captured with.
+	ReplayRealtime ReplayMode = "realtime"
+	// ReplayFast delivers packets as fast as they can be read.
+	ReplayFast ReplayMode = "fast"
+)
+
+func ParseReplayMode(s string) (ReplayMode, error) {
+	switch mode := ReplayMode(s); mode {
+	case ReplayRealtime, ReplayFast:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown replay mode %q", s)
+	}
+}
+
+// replayPoll bounds a single wait of a realtime replay, so the reader can
+// check for cancellation between packets that were captured far apart.
+const replayPoll = 500 * time.Millisecond
+
+const (
+	pcapMagicMicro = 0xA1B2C3D4
+	pcapMagicNano  = 0xA1B23C4D
+	pcapngSHB      = 0x0A0D0D0A
+	pcapngBOM      = 0x1A2B3C4D
+
+	pcapngIDB        = 0x00000001
+	pcapngObsoletePB = 0x00000002
+	pcapngSPB        = 0x00000003
+	pcapngEPB        = 0x00000006
+	pcapngOptEnd     = 0
+	pcapngOptTSResol = 9
+
+	maxRecordSize = 256 << 20
+)
+
+var ErrBadCaptureFile = errors.New("not a pcap or pcapng file")
+
+// record is one packet of a capture file.
+type record struct {
+	timestamp time.Time
+	length    int
+	linkType  LinkType
+	data      []byte
+}
+
+type recordReader interface {
+	// next returns io.EOF after the last record.
+	next() (record, error)
+}
+
+type fileSource struct {
+	file    *os.File
+	records recordReader
+	mode    ReplayMode
+
+	pending     *record
+	started     time.Time
+	firstPacket time.Time
+}
+
+// OpenFile opens a pcap or pcapng file as a packet source. The packets keep
+// the timestamps of the file.
+func OpenFile(path string, mode ReplayMode) (Source, error) {
+	if _, err := ParseReplayMode(string(mode)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	records, err := newRecordReader(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &fileSource{file: f, records: records, mode: mode}, nil
+}
+
+func newRecordReader(r *bufio.Reader) (recordReader, error) {
+	magic, err := r.Peek(4)
+	if err != nil {
+		return nil, ErrBadCaptureFile
+	}
+	if binary.LittleEndian.Uint32(magic) == pcapngSHB {
+		return &pcapngReader{r: r}, nil
+	}
+	return newPcapReader(r)
+}
+
+// ReadPacket returns the next packet of the file and io.EOF after the last one.
+func (s *fileSource) ReadPacket(buf []byte) (int, CaptureInfo, error) {
+	if s.pending == nil {
+		rec, err := s.records.next()
+		if err != nil {
+			return 0, CaptureInfo{}, err
+		}
+		s.pending = &rec
+	}
+	rec := s.pending
+
+	if s.mode == ReplayRealtime {
+		if s.started.IsZero() {
+			s.started, s.firstPacket = time.Now(), rec.timestamp
+		}
+		wait := time.Until(s.started.Add(rec.timestamp.Sub(s.firstPacket)))
+		if wait > replayPoll {
+			time.Sleep(replayPoll)
+			return 0, CaptureInfo{}, ErrTimeout
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+
+	s.pending = nil
+	info := CaptureInfo{Timestamp: rec.timestamp, Length: rec.length, LinkType: rec.linkType}
+	return copy(buf, rec.data), info, nil
+}
+
+func (s *fileSource) Close() error {
+	return s.file.Close()
+}
+
+// pcapReader reads the classic libpcap format.
+type pcapReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType LinkType
+	header   [16]byte
+}
+
+func newPcapReader(r io.Reader) (*pcapReader, error) {
+	var header [24]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, ErrBadCaptureFile
+	}
+
+	p := &pcapReader{r: r}
+	switch {
+	case binary.LittleEndian.Uint32(header[0:4]) == pcapMagicMicro:
+		p.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(header[0:4]) == pcapMagicMicro:
+		p.order = binary.BigEndian
+	case binary.LittleEndian.Uint32(header[0:4]) == pcapMagicNano:
+		p.order, p.nano = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(header[0:4]) == pcapMagicNano:
+		p.order, p.nano = binary.BigEndian, true
+	default:
+		return nil, ErrBadCaptureFile
+	}
+	// The upper bits of the link type field carry FCS information.
+	p.linkType = LinkType(p.order.Uint32(header[20:24]) & 0xFFFF)
+	return p, nil
+}
+
+func (p *pcapReader) next() (record, error) {
+	if _, err := io.ReadFull(p.r, p.header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return record{}, fmt.Errorf("record header: %w", ErrTruncated)
+		}
+		return record{}, err
+	}
+
+	sec := int64(p.order.Uint32(p.header[0:4]))
+	frac := int64(p.order.Uint32(p.header[4:8]))
+	if !p.nano {
+		frac *= int64(time.Microsecond)
+	}
+	captured := p.order.Uint32(p.header[8:12])
+	if captured > maxRecordSize {
+		return record{}, fmt.Errorf("record of %d bytes: %w", captured, ErrBadCaptureFile)
+	}
+
+	data := make([]byte, captured)
+	if _, err := io.ReadFull(p.r, data); err != nil {
+		return record{}, fmt.Errorf("record data: %w", ErrTruncated)
+	}
+	return record{
+		timestamp: time.Unix(sec, frac),
+		length:    int(p.order.Uint32(p.header[12:16])),
+		linkType:  p.linkType,
+		data:      data,
+	}, nil
+}
+
+type pcapngInterface struct {
+	linkType LinkType
+	// units is the number of timestamp units per second.
+	units uint64
+}
+
+// pcapngReader reads the pcapng format. Blocks other than packets and the
+// ones describing interfaces are skipped.
+type pcapngReader struct {
+	r          io.Reader
+	order      binary.ByteOrder
+	interfaces []pcapngInterface
+}
+
+func (p *pcapngReader) next() (record, error) {
+	for {
+		blockType, body, err := p.readBlock()
+		if err != nil {
+			return record{}, err
+		}
+
+		switch blockType {
+		case pcapngIDB:
+			if err := p.addInterface(body); err != nil {
+				return record{}, err
+			}
+		case pcapngEPB, pcapngObsoletePB:
+			return p.packet(blockType, body)
+		case pcapngSPB:
+			if len(body) < 4 {
+				return record{}, fmt.Errorf("simple packet block: %w", ErrTruncated)
+			}
+			iface, err := p.iface(0)
+			if err != nil {
+				return record{}, err
+			}
+			length := int(p.order.Uint32(body[0:4]))
+			data := body[4:]
+			if len(data) > length {
+				data = data[:length]
+			}
+			return record{length: length, linkType: iface.linkType, data: data}, nil
+		}
+	}
+}
+
+// readBlock returns the body of the next block. A section header block sets
+// the byte order and starts a new list of interfaces.
+func (p *pcapngReader) readBlock() (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(p.r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, nil, fmt.Errorf("block header: %w", ErrTruncated)
+		}
+		return 0, nil, err
+	}
+
+	if binary.LittleEndian.Uint32(header[0:4]) == pcapngSHB {
+		var bom [4]byte
+		if _, err := io.ReadFull(p.r, bom[:]); err != nil {
+			return 0, nil, fmt.Errorf("section header: %w", ErrTruncated)
+		}
+		switch {
+		case binary.LittleEndian.Uint32(bom[:]) == pcapngBOM:
+			p.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(bom[:]) == pcapngBOM:
+			p.order = binary.BigEndian
+		default:
+			return 0, nil, ErrBadCaptureFile
+		}
+		p.interfaces = nil
+
+		length := p.order.Uint32(header[4:8])
+		if length < 28 || length%4 != 0 || length > maxRecordSize {
+			return 0, nil, fmt.Errorf("section header of %d bytes: %w", length, ErrBadCaptureFile)
+		}
+		if _, err := io.CopyN(io.Discard, p.r, int64(length-12)); err != nil {
+			return 0, nil, fmt.Errorf("section header: %w", ErrTruncated)
+		}
+		return pcapngSHB, nil, nil
+	}
+	if p.order == nil {
+		return 0, nil, ErrBadCaptureFile
+	}
+
+	blockType := p.order.Uint32(header[0:4])
+	length := p.order.Uint32(header[4:8])
+	if length < 12 || length%4 != 0 || length > maxRecordSize {
+		return 0, nil, fmt.Errorf("block of %d bytes: %w", length, ErrBadCaptureFile)
+	}
+	block := make([]byte, length-8)
+	if _, err := io.ReadFull(p.r, block); err != nil {
+		return 0, nil, fmt.Errorf("block body: %w", ErrTruncated)
+	}
+	// The block ends with a copy of its length.
+	return blockType, block[:len(block)-4], nil
+}
+
+func (p *pcapngReader) addInterface(body []byte) error {
+	if len(body) < 8 {
+		return fmt.Errorf("interface description block: %w", ErrTruncated)
+	}
+	iface := pcapngInterface{
+		linkType: LinkType(p.order.Uint16(body[0:2])),
+		units:    1_000_000,
+	}
+
+	options := body[8:]
+	for len(options) >= 4 {
+		code := p.order.Uint16(options[0:2])
+		length := int(p.order.Uint16(options[2:4]))
+		options = options[4:]
+		if code == pcapngOptEnd || length > len(options) {
+			break
+		}
+		if code == pcapngOptTSResol && length >= 1 {
+			iface.units = timestampUnits(options[0])
+		}
+		options = options[min((length+3)&^3, len(options)):]
+	}
+
+	p.interfaces = append(p.interfaces, iface)
+	return nil
+}
+
+// timestampUnits decodes if_tsresol: a negative power of 10, or of 2 when the
+// high bit is set.
+func timestampUnits(resolution byte) uint64 {
+	exponent := resolution & 0x7F
+	base := uint64(10)
+	if resolution&0x80 != 0 {
+		base = 2
+	}
+	units := uint64(1)
+	for i := byte(0); i < exponent && units <= 1<<60/base; i++ {
+		units *= base
+	}
+	return units
+}
+
+func (p *pcapngReader) iface(id uint32) (pcapngInterface, error) {
+	if int(id) >= len(p.interfaces) {
+		return pcapngInterface{}, fmt.Errorf("packet of undescribed interface %d: %w", id, ErrBadCaptureFile)
+	}
+	return p.interfaces[id], nil
+}
+
+// packet decodes an enhanced packet block or the obsolete packet block, which
+// only differs by a 16-bit interface id followed by a drop counter.
+func (p *pcapngReader) packet(blockType uint32, body []byte) (record, error) {
+	if len(body) < 20 {
+		return record{}, fmt.Errorf("packet block: %w", ErrTruncated)
+	}
+	id := p.order.Uint32(body[0:4])
+	if blockType == pcapngObsoletePB {
+		id = uint32(p.order.Uint16(body[0:2]))
+	}
+	iface, err := p.iface(id)
+	if err != nil {
+		return record{}, err
+	}
+
+	captured := p.order.Uint32(body[12:16])
+	if uint64(captured) > uint64(len(body)-20) {
+		return record{}, fmt.Errorf("packet block data: %w", ErrTruncated)
+	}
+
+	ts := uint64(p.order.Uint32(body[4:8]))<<32 | uint64(p.order.Uint32(body[8:12]))
+	sec, frac := ts/iface.units, ts%iface.units
+	hi, lo := bits.Mul64(frac, uint64(time.Second))
+	nsec, _ := bits.Div64(hi, lo, iface.units)
+	return record{
+		timestamp: time.Unix(int64(sec), int64(nsec)),
+		length:    int(p.order.Uint32(body[16:20])),
+		linkType:  iface.linkType,
+		data:      body[20 : 20+captured],
+	}, nil
+}
@@ -0,0 +1,232 @@
+package capture
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPacket struct {
+	timestamp time.Time
+	length    int
+	data      []byte
+}
+
+func writePcap(t *testing.T, order binary.AppendByteOrder, nano bool, linkType LinkType, packets []testPacket) string {
+	t.Helper()
+	magic := uint32(pcapMagicMicro)
+	if nano {
+		magic = pcapMagicNano
+	}
+	b := order.AppendUint32(nil, magic)
+	b = order.AppendUint16(b, 2)
+	b = order.AppendUint16(b, 4)
+	b = order.AppendUint32(b, 0)
+	b = order.AppendUint32(b, 0)
+	b = order.AppendUint32(b, 65535)
+	b = order.AppendUint32(b, uint32(linkType))
+
+	for _, p := range packets {
+		frac := uint32(p.timestamp.Nanosecond())
+		if !nano {
+			frac /= 1000
+		}
+		b = order.AppendUint32(b, uint32(p.timestamp.Unix()))
+		b = order.AppendUint32(b, frac)
+		b = order.AppendUint32(b, uint32(len(p.data)))
+		b = order.AppendUint32(b, uint32(p.length))
+		b = append(b, p.data...)
+	}
+	return writeCaptureFile(t, "test.pcap", b)
+}
+
+func pcapngBlock(order binary.AppendByteOrder, blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	b := order.AppendUint32(nil, blockType)
+	b = order.AppendUint32(b, uint32(12+len(body)))
+	b = append(b, body...)
+	return order.AppendUint32(b, uint32(12+len(body)))
+}
+
+func pcapngSection(order binary.AppendByteOrder) []byte {
+	body := order.AppendUint32(nil, pcapngBOM)
+	body = order.AppendUint16(body, 1)
+	body = order.AppendUint16(body, 0)
+	body = order.AppendUint64(body, ^uint64(0))
+	return pcapngBlock(order, pcapngSHB, body)
+}
+
+func pcapngIface(order binary.AppendByteOrder, linkType LinkType, tsresol byte) []byte {
+	body := order.AppendUint16(nil, uint16(linkType))
+	body = order.AppendUint16(body, 0)
+	body = order.AppendUint32(body, 0)
+	body = order.AppendUint16(body, pcapngOptTSResol)
+	body = order.AppendUint16(body, 1)
+	body = append(body, tsresol, 0, 0, 0)
+	body = order.AppendUint32(body, pcapngOptEnd)
+	return pcapngBlock(order, pcapngIDB, body)
+}
+
+func pcapngPacket(order binary.AppendByteOrder, iface uint32, ts uint64, p testPacket) []byte {
+	body := order.AppendUint32(nil, iface)
+	body = order.AppendUint32(body, uint32(ts>>32))
+	body = order.AppendUint32(body, uint32(ts))
+	body = order.AppendUint32(body, uint32(len(p.data)))
+	body = order.AppendUint32(body, uint32(p.length))
+	body = append(body, p.data...)
+	return pcapngBlock(order, pcapngEPB, body)
+}
+
+func writeCaptureFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func readAll(t *testing.T, source Source) ([][]byte, []CaptureInfo) {
+	t.Helper()
+	defer source.Close()
+
+	var frames [][]byte
+	var infos []CaptureInfo
+	buf := make([]byte, 64)
+	for {
+		n, info, err := source.ReadPacket(buf)
+		if err == io.EOF {
+			return frames, infos
+		}
+		require.NoError(t, err)
+		frames = append(frames, append([]byte(nil), buf[:n]...))
+		infos = append(infos, info)
+	}
+}
+
+func TestOpenFile_Pcap(t *testing.T) {
+	tcp := ethernet(etherTypeIPv4, ipv4(ipProtoTCP, "10.0.0.1", "10.0.0.2", 0, ports(443, 51234)))
+	large := make([]byte, 100)
+	start := time.Unix(1700000000, 123456000)
+
+	for _, tt := range []struct {
+		name  string
+		order binary.AppendByteOrder
+		nano  bool
+	}{
+		{"little endian", binary.LittleEndian, false},
+		{"big endian nanoseconds", binary.BigEndian, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePcap(t, tt.order, tt.nano, LinkTypeEthernet, []testPacket{
+				{timestamp: start, length: len(tcp), data: tcp},
+				{timestamp: start.Add(time.Second), length: 1500, data: large},
+			})
+			source, err := OpenFile(path, ReplayFast)
+			require.NoError(t, err)
+
+			frames, infos := readAll(t, source)
+			require.Equal(t, [][]byte{tcp, large[:64]}, frames)
+			require.Equal(t, []CaptureInfo{
+				{Timestamp: start, Length: len(tcp), LinkType: LinkTypeEthernet},
+				{Timestamp: start.Add(time.Second), Length: 1500, LinkType: LinkTypeEthernet},
+			}, infos)
+		})
+	}
+}
+
+func TestOpenFile_Pcapng(t *testing.T) {
+	order := binary.BigEndian
+	udp := ipv4(ipProtoUDP, "10.0.0.1", "10.0.0.2", 0, ports(5353, 53))
+	arp := ethernet(etherTypeARP, make([]byte, 28))
+
+	var b []byte
+	b = append(b, pcapngSection(order)...)
+	b = append(b, pcapngIface(order, LinkTypeRaw, 9)...)
+	// Name resolution block, skipped.
+	b = append(b, pcapngBlock(order, 4, []byte{0, 0, 0, 0})...)
+	b = append(b, pcapngPacket(order, 0, 1700000000_000000001, testPacket{length: len(udp), data: udp})...)
+	// A new section starts a new list of interfaces.
+	b = append(b, pcapngSection(binary.LittleEndian)...)
+	b = append(b, pcapngIface(binary.LittleEndian, LinkTypeEthernet, 0x80|10)...)
+	b = append(b, pcapngPacket(binary.LittleEndian, 0, 1024*3+512, testPacket{length: 60, data: arp})...)
+	path := writeCaptureFile(t, "test.pcapng", b)
+
+	source, err := OpenFile(path, ReplayFast)
+	require.NoError(t, err)
+	frames, infos := readAll(t, source)
+	require.Equal(t, [][]byte{udp, arp}, frames)
+	require.Equal(t, []CaptureInfo{
+		{Timestamp: time.Unix(1700000000, 1), Length: len(udp), LinkType: LinkTypeRaw},
+		{Timestamp: time.Unix(3, 500000000), Length: 60, LinkType: LinkTypeEthernet},
+	}, infos)
+}
+
+func TestOpenFile_Errors(t *testing.T) {
+	_, err := OpenFile(writeCaptureFile(t, "text", []byte("hello, world: not a capture")), ReplayFast)
+	require.ErrorIs(t, err, ErrBadCaptureFile)
+
+	path := writePcap(t, binary.LittleEndian, false, LinkTypeEthernet, []testPacket{{data: make([]byte, 10)}})
+	_, err = OpenFile(path, "slow")
+	require.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	source, err := OpenFile(writeCaptureFile(t, "truncated.pcap", data[:len(data)-1]), ReplayFast)
+	require.NoError(t, err)
+	defer source.Close()
+	_, _, err = source.ReadPacket(make([]byte, 64))
+	require.ErrorIs(t, err, ErrTruncated)
+}
+
+func TestOpenFile_RealtimeReplay(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	frame := ethernet(etherTypeARP, make([]byte, 28))
+	path := writePcap(t, binary.LittleEndian, false, LinkTypeEthernet, []testPacket{
+		{timestamp: start, length: len(frame), data: frame},
+		{timestamp: start.Add(150 * time.Millisecond), length: len(frame), data: frame},
+		{timestamp: start.Add(300 * time.Millisecond), length: len(frame), data: frame},
+	})
+	source, err := OpenFile(path, ReplayRealtime)
+	require.NoError(t, err)
+
+	began := time.Now()
+	frames, _ := readAll(t, source)
+	require.Len(t, frames, 3)
+	require.GreaterOrEqual(t, time.Since(began), 300*time.Millisecond)
+}
+
+func TestEngine_ReplaysFile(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	var packets []testPacket
+	for i := 0; i < 5; i++ {
+		frame := ethernet(etherTypeIPv4, ipv4(ipProtoTCP, "10.0.0.1", "10.0.0.2", 0, ports(443, 51234)))
+		packets = append(packets, testPacket{timestamp: start.Add(time.Duration(i) * time.Second), length: 1000, data: frame})
+	}
+	udp := ethernet(etherTypeIPv4, ipv4(ipProtoUDP, "10.0.0.3", "10.0.0.2", 0, ports(5353, 53)))
+	packets = append(packets, testPacket{timestamp: start.Add(10 * time.Second), length: 200, data: udp})
+
+	source, err := OpenFile(writePcap(t, binary.LittleEndian, false, LinkTypeEthernet, packets), ReplayFast)
+	require.NoError(t, err)
+
+	table := NewFlowTable(10)
+	engine := NewEngine(source, 256)
+	engine.AddHandler(table.Observe)
+	require.NoError(t, engine.Run(context.Background()))
+
+	flows, first, last := table.Drain()
+	require.Len(t, flows, 2)
+	require.Equal(t, start, first)
+	require.Equal(t, start.Add(10*time.Second), last)
+	require.Equal(t, uint64(5000), flows[0].Bytes)
+	require.Equal(t, uint64(200), flows[1].Bytes)
+	packetCount, decodeErrors := engine.Stats()
+	require.Equal(t, uint64(6), packetCount)
+	require.Zero(t, decodeErrors)
+}
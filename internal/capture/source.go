@@ -0,0 +1,98 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrNotSupported = errors.New("packet capture is not supported on this platform")
+
+// Source produces captured frames. ReadPacket returns ErrTimeout when no
+// packet arrived in time, which lets the reader check for cancellation.
+type Source interface {
+	ReadPacket(buf []byte) (n int, info CaptureInfo, err error)
+	Close() error
+}
+
+type CaptureInfo struct {
+	Timestamp time.Time
+	Length    int
+	LinkType  LinkType
+	// Skip marks duplicates the source knows about, e.g. outgoing copies of
+	// loopback packets.
+	Skip bool
+}
+
+var ErrTimeout = errors.New("capture read timeout")
+
+type Handler func(p *Packet)
+
+// Engine reads a source, decodes every packet and passes it to the handlers.
+type Engine struct {
+	source   Source
+	snapLen  int
+	mu       sync.RWMutex
+	handlers []Handler
+
+	packets      atomic.Uint64
+	decodeErrors atomic.Uint64
+}
+
+func NewEngine(source Source, snapLen int) *Engine {
+	return &Engine{source: source, snapLen: snapLen}
+}
+
+func (e *Engine) AddHandler(h Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.handlers = append(e.handlers, h)
+}
+
+// Run reads packets until ctx is done or the source is exhausted. The source
+// is closed on return.
+func (e *Engine) Run(ctx context.Context) error {
+	defer e.source.Close()
+
+	buf := make([]byte, e.snapLen)
+	var p Packet
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		n, info, err := e.source.ReadPacket(buf)
+		switch {
+		case errors.Is(err, ErrTimeout):
+			continue
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+		if info.Skip {
+			continue
+		}
+
+		p.Timestamp, p.Length = info.Timestamp, info.Length
+		if err := Decode(info.LinkType, buf[:n], &p); err != nil {
+			e.decodeErrors.Add(1)
+		}
+		e.packets.Add(1)
+
+		e.mu.RLock()
+		for _, h := range e.handlers {
+			h(&p)
+		}
+		e.mu.RUnlock()
+	}
+}
+
+// Stats returns the number of packets processed and how many of them could
+// not be fully decoded.
+func (e *Engine) Stats() (packets, decodeErrors uint64) {
+	return e.packets.Load(), e.decodeErrors.Load()
+}
@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/dimryb/system-monitor/internal/capture"
+	"github.com/dimryb/system-monitor/internal/config"
+)
+
+// sharedCapture is the packet capture shared by the traffic collectors. It
+// runs while at least one of them is started and stops with the last one. A
+// permission error is remembered and reported as ErrUnavailable, so an
+// unprivileged daemon does not retry on every tick. A replayed file is read to the end once; afterwards the
+// collectors see no traffic. A replay stopped before the end starts over.
+type sharedCapture struct {
+	open    func() (capture.Source, error)
+	replay  bool
+	snapLen int
+
+	mu        sync.Mutex
+	handlers  []capture.Handler
+	users     map[string]bool
+	stop      context.CancelFunc
+	finished  bool
+	runErr    error
+	permanent error
+}
+
+func newSharedCapture(cfg config.CaptureConfig) (*sharedCapture, error) {
+	c := &sharedCapture{snapLen: cfg.SnapLen, users: make(map[string]bool)}
+	if cfg.File == "" {
+		c.open = func() (capture.Source, error) {
+			return capture.OpenLive(cfg.Interface)
+		}
+		return c, nil
+	}
+
+	mode, err := capture.ParseReplayMode(cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	c.replay = true
+	c.open = func() (capture.Source, error) {
+		return capture.OpenFile(cfg.File, mode)
+	}
+	return c, nil
+}
+
+func (c *sharedCapture) AddHandler(h capture.Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, h)
+}
+
+// Start makes sure the capture runs for user. It is called on every
+// collection and returns nil when the capture is already running.
+func (c *sharedCapture) Start(user string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[user] = true
+
+	switch {
+	case c.stop != nil, c.finished:
+		return nil
+	case c.permanent != nil:
+		return unavailable(c.permanent)
+	case c.runErr != nil:
+		err := c.runErr
+		c.runErr = nil
+		return fmt.Errorf("capture stopped: %w", err)
+	}
+
+	source, err := c.open()
+	if err != nil {
+		if c.replay || errors.Is(err, os.ErrPermission) || errors.Is(err, capture.ErrNotSupported) {
+			c.permanent = err
+			return unavailable(err)
+		}
+		return err
+	}
+	engine := capture.NewEngine(source, c.snapLen)
+	for _, h := range c.handlers {
+		engine.AddHandler(h)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stop = cancel
+	go func() {
+		defer cancel()
+		err := engine.Run(ctx)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// A capture stopped by Stop leaves the state to the next Start.
+		if ctx.Err() == nil {
+			c.stop, c.runErr = nil, err
+			c.finished = c.replay && err == nil
+		}
+	}()
+	return nil
+}
+
+// Stop releases the capture for user and stops it when nobody else uses it.
+func (c *sharedCapture) Stop(user string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.users, user)
+	if len(c.users) == 0 && c.stop != nil {
+		c.stop()
+		c.stop = nil
+	}
+}
@@ -0,0 +1,253 @@
+package collector
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+type cgroupCounters struct {
+	usageUsec  uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// resetSince reports whether a counter went back since prev: the cgroup was
+// removed and created again at the same path, as on a restart of its unit.
+func (c cgroupCounters) resetSince(prev cgroupCounters) bool {
+	return c.usageUsec < prev.usageUsec || c.readBytes < prev.readBytes || c.writeBytes < prev.writeBytes
+}
+
+type cgroupSample struct {
+	path        string
+	counters    cgroupCounters
+	memory      uint64
+	memoryLimit uint64
+	pids        uint64
+}
+
+// cgroupsCollector reports the cgroups of the v2 hierarchy using the most CPU
+// and the most memory, with the CPU and io rates between the previous and the
+// current call. The first call only remembers the counters, and so does the
+// first call after a cgroup was created again.
+type cgroupsCollector struct {
+	root     string
+	top      int
+	prev     map[string]cgroupCounters
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newCgroupsCollector(sysRoot string, top int) *cgroupsCollector {
+	return &cgroupsCollector{root: filepath.Join(sysRoot, "fs", "cgroup"), top: top, now: time.Now}
+}
+
+func (c *cgroupsCollector) Name() string {
+	return string(entity.CategoryCgroups)
+}
+
+func (c *cgroupsCollector) ReportsRates() {}
+
+// Available reports an error unless cgroup v2 is mounted: the hierarchies of
+// cgroup v1 have no cgroup.controllers.
+func (c *cgroupsCollector) Available() error {
+	return fileAvailable(filepath.Join(c.root, "cgroup.controllers"))
+}
+
+func (c *cgroupsCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	now := c.now()
+	samples, err := c.walk()
+	if err != nil {
+		return err
+	}
+
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = make(map[string]cgroupCounters, len(samples)), now
+
+	cgroups := make([]entity.CgroupStats, 0, len(samples))
+	for _, s := range samples {
+		c.prev[s.path] = s.counters
+
+		before, ok := prev[s.path]
+		if !ok || elapsed <= 0 || s.counters.resetSince(before) {
+			continue
+		}
+		cgroups = append(cgroups, cgroupRates(s, before, elapsed))
+	}
+
+	if prev != nil {
+		m.Cgroups = topCgroups(cgroups, c.top)
+	}
+	return nil
+}
+
+// walk reads every cgroup below the root, which accounts for the whole host
+// and is left out. Cgroups removed during the walk are skipped.
+func (c *cgroupsCollector) walk() ([]cgroupSample, error) {
+	var samples []cgroupSample
+	err := filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path != c.root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() || path == c.root {
+			return nil
+		}
+
+		s, err := readCgroup(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(c.root, path)
+		if err != nil {
+			return err
+		}
+		s.path = "/" + filepath.ToSlash(rel)
+		samples = append(samples, s)
+		return nil
+	})
+	return samples, err
+}
+
+// readCgroup reads the files of a cgroup. Only cpu.stat is always there, the
+// files of the memory, io and pids controllers are missing when the
+// controller is not enabled for the cgroup.
+func readCgroup(dir string) (cgroupSample, error) {
+	var s cgroupSample
+
+	stat, err := readNameValues(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return s, err
+	}
+	s.counters.usageUsec = stat["usage_usec"]
+
+	for _, v := range []struct {
+		file  string
+		value *uint64
+	}{
+		{"memory.current", &s.memory},
+		{"memory.max", &s.memoryLimit},
+		{"pids.current", &s.pids},
+	} {
+		if *v.value, err = readCgroupValue(filepath.Join(dir, v.file)); err != nil {
+			return s, err
+		}
+	}
+
+	path := filepath.Join(dir, "io.stat")
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	s.counters.readBytes, s.counters.writeBytes, err = parseIOStat(f)
+	if err != nil {
+		return s, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// readCgroupValue reads a file holding a single number. A missing file and
+// "max", no limit, read as zero.
+func readCgroupValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// parseIOStat sums the bytes read and written on every device of an io.stat:
+//
+//	8:0 rbytes=90112 wbytes=0 rios=3 wios=0 dbytes=0 dios=0
+func parseIOStat(r io.Reader) (read, written uint64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok || (key != "rbytes" && key != "wbytes") {
+				continue
+			}
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("%s %s: %w", fields[0], key, err)
+			}
+			if key == "rbytes" {
+				read += v
+			} else {
+				written += v
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	return read, written, nil
+}
+
+func cgroupRates(s cgroupSample, prev cgroupCounters, elapsed float64) entity.CgroupStats {
+	const bytesPerMB = 1 << 20
+	return entity.CgroupStats{
+		Path:            s.path,
+		CPUPercent:      float64(s.counters.usageUsec-prev.usageUsec) / (elapsed * 1e6) * 100,
+		MemoryMB:        s.memory / bytesPerMB,
+		MemoryLimitMB:   s.memoryLimit / bytesPerMB,
+		IOReadKBPerSec:  float64(s.counters.readBytes-prev.readBytes) / 1024 / elapsed,
+		IOWriteKBPerSec: float64(s.counters.writeBytes-prev.writeBytes) / 1024 / elapsed,
+		Pids:            s.pids,
+	}
+}
+
+// topCgroups keeps the top cgroups by CPU and the top ones by memory, sorted
+// by CPU and then by memory.
+func topCgroups(cgroups []entity.CgroupStats, top int) []entity.CgroupStats {
+	top = min(max(top, 0), len(cgroups))
+	keep := make(map[string]bool, 2*top)
+
+	slices.SortStableFunc(cgroups, func(a, b entity.CgroupStats) int { return cmp.Compare(b.MemoryMB, a.MemoryMB) })
+	for _, g := range cgroups[:top] {
+		keep[g.Path] = true
+	}
+	slices.SortStableFunc(cgroups, func(a, b entity.CgroupStats) int { return cmp.Compare(b.CPUPercent, a.CPUPercent) })
+	for _, g := range cgroups[:top] {
+		keep[g.Path] = true
+	}
+	return slices.DeleteFunc(cgroups, func(g entity.CgroupStats) bool { return !keep[g.Path] })
+}
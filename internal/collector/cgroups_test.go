@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+func TestParseIOStat(t *testing.T) {
+	read, written, err := parseIOStat(strings.NewReader(
+		"8:0 rbytes=90112 wbytes=4096 rios=3 wios=1 dbytes=0 dios=0\n259:0 rbytes=10 wbytes=20 rios=1 wios=1 dbytes=0 dios=0\n"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(90122), read)
+	require.Equal(t, uint64(4116), written)
+
+	_, _, err = parseIOStat(strings.NewReader("8:0 rbytes=x\n"))
+	require.Error(t, err)
+}
+
+func TestTopCgroups(t *testing.T) {
+	cgroups := []entity.CgroupStats{
+		{Path: "/a", CPUPercent: 5, MemoryMB: 10},
+		{Path: "/b", CPUPercent: 90, MemoryMB: 20},
+		{Path: "/c", CPUPercent: 1, MemoryMB: 4000},
+		{Path: "/d", CPUPercent: 40, MemoryMB: 30},
+	}
+
+	require.Equal(t, []entity.CgroupStats{
+		{Path: "/b", CPUPercent: 90, MemoryMB: 20},
+		{Path: "/c", CPUPercent: 1, MemoryMB: 4000},
+	}, topCgroups(cgroups, 1))
+	require.Empty(t, topCgroups(cgroups, 0))
+}
+
+func TestCgroupsCollector(t *testing.T) {
+	sys := t.TempDir()
+	root := filepath.Join(sys, "fs", "cgroup")
+	write := func(cgroup, file, data string) {
+		writeFile(t, filepath.Join(root, cgroup, file), data)
+	}
+	write("", "cgroup.controllers", "cpu io memory pids\n")
+	write("", "cpu.stat", "usage_usec 1000000\n")
+	write("app.service", "memory.current", "104857600\n")
+	write("app.service", "memory.max", "max\n")
+	write("app.service", "pids.current", "7\n")
+	// No controller is enabled for the cgroup: it only has cpu.stat.
+	write("init.scope", "cpu.stat", "usage_usec 10\n")
+	usage := func(app, readBytes int) {
+		write("app.service", "cpu.stat", fmt.Sprintf("usage_usec %d\nuser_usec 0\nsystem_usec 0\n", app))
+		write("app.service", "io.stat", fmt.Sprintf("8:0 rbytes=%d wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n", readBytes))
+	}
+
+	now := time.Unix(1700000000, 0)
+	c := newCgroupsCollector(sys, 10)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Available())
+
+	usage(1000000, 0)
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Nil(t, m.Cgroups)
+
+	// A cgroup created since the previous call has no rates yet.
+	write("new.scope", "cpu.stat", "usage_usec 500\n")
+	usage(2500000, 2097152)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.CgroupStats{
+		{Path: "/app.service", CPUPercent: 75, MemoryMB: 100, IOReadKBPerSec: 1024, Pids: 7},
+		{Path: "/init.scope"},
+	}, m.Cgroups)
+}
+
+func TestCgroupsCollector_Restart(t *testing.T) {
+	sys := t.TempDir()
+	root := filepath.Join(sys, "fs", "cgroup")
+	writeFile(t, filepath.Join(root, "cgroup.controllers"), "cpu io memory pids\n")
+	usage := func(usec, readBytes int) {
+		writeFile(t, filepath.Join(root, "app.service", "cpu.stat"), fmt.Sprintf("usage_usec %d\n", usec))
+		writeFile(t, filepath.Join(root, "app.service", "io.stat"),
+			fmt.Sprintf("8:0 rbytes=%d wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n", readBytes))
+	}
+
+	now := time.Unix(1700000000, 0)
+	c := newCgroupsCollector(sys, 10)
+	c.now = func() time.Time { return now }
+	collect := func() []entity.CgroupStats {
+		m := &entity.SystemMetrics{}
+		require.NoError(t, c.Collect(context.Background(), m))
+		return m.Cgroups
+	}
+
+	usage(9000000, 1048576)
+	collect()
+
+	// The unit restarted: its cgroup was created again with new counters.
+	usage(20000, 0)
+	now = now.Add(time.Second)
+	require.Empty(t, collect())
+
+	usage(520000, 1048576)
+	now = now.Add(time.Second)
+	require.Equal(t, []entity.CgroupStats{
+		{Path: "/app.service", CPUPercent: 50, IOReadKBPerSec: 1024},
+	}, collect())
+}
+
+func TestCgroupsCollector_V1(t *testing.T) {
+	sys := t.TempDir()
+	writeFile(t, filepath.Join(sys, "fs", "cgroup", "memory", "memory.usage_in_bytes"), "4096\n")
+
+	require.Error(t, newCgroupsCollector(sys, 10).Available())
+}
@@ -3,29 +3,85 @@ package collector
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 )
 
+// Only the beginning of the output of a command is kept, so a chatty command
+// cannot exhaust the memory of the daemon.
+const (
+	maxCommandStdout = 1 << 20
+	maxCommandStderr = 4 << 10
+)
+
+// commandWaitDelay bounds the wait for the output of the children of a killed
+// command, which may keep its pipes open.
+const commandWaitDelay = time.Second
+
+// commandCollector runs a program with a timeout. The command line is passed
+// to sh -c when shell is set, otherwise no shell is involved.
 type commandCollector struct {
 	command string
+	args    []string
+	shell   bool
 	timeout time.Duration
 }
 
-func NewCommandCollector(command string, timeout time.Duration) *commandCollector {
-	return &commandCollector{command, timeout}
+// commandResult is what a command left behind. ExitCode is -1 when the
+// command did not exit by itself: it failed to start or was killed.
+type commandResult struct {
+	stdout   string
+	stderr   string
+	exitCode int32
 }
 
-func (c commandCollector) Collect(ctx context.Context) (string, error) {
+func newCommandCollector(command string, args []string, shell bool, timeout time.Duration) *commandCollector {
+	return &commandCollector{command: command, args: args, shell: shell, timeout: timeout}
+}
+
+// Collect runs the command. The result is filled as far as the command got,
+// also when it fails.
+func (c *commandCollector) Collect(ctx context.Context) (commandResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bash", "-c", c.command) //nolint:gosec
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return "", err
+	name, args := c.command, c.args
+	if c.shell {
+		name, args = "sh", []string{"-c", c.command}
+	}
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec
+	stdout := &limitedBuffer{limit: maxCommandStdout}
+	stderr := &limitedBuffer{limit: maxCommandStderr}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	err := cmd.Run()
+	result := commandResult{
+		stdout:   strings.TrimSpace(stdout.String()),
+		stderr:   strings.TrimSpace(stderr.String()),
+		exitCode: -1,
+	}
+	if cmd.ProcessState != nil {
+		result.exitCode = int32(cmd.ProcessState.ExitCode())
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+	return result, err
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
 	}
-	return strings.TrimSpace(out.String()), nil
+	return len(p), nil
 }
@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const maxStatLine = 1 << 20
+
+// cpuTimes holds the jiffy counters of one "cpu" line of /proc/stat.
+type cpuTimes struct {
+	user, nice, system, idle, iowait, irq, softirq, steal uint64
+}
+
+func (t cpuTimes) total() uint64 {
+	// guest and guest_nice are already accounted in user and nice.
+	return t.user + t.nice + t.system + t.idle + t.iowait + t.irq + t.softirq + t.steal
+}
+
+type cpuStat struct {
+	total cpuTimes
+	cores map[string]cpuTimes
+	order []string
+}
+
+type cpuCollector struct {
+	path    string
+	perCore bool
+	prev    *cpuStat
+}
+
+func newCPUCollector(procRoot string, perCore bool) *cpuCollector {
+	return &cpuCollector{path: filepath.Join(procRoot, "stat"), perCore: perCore}
+}
+
+func (c *cpuCollector) Name() string {
+	return string(entity.CategoryCPU)
+}
+
+func (c *cpuCollector) ReportsRates() {}
+
+func (c *cpuCollector) Available() error {
+	return fileAvailable(c.path)
+}
+
+// Collect reports CPU usage between the previous and the current call. The
+// first call only remembers the counters and leaves m.CPU empty.
+func (c *cpuCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", c.path, err)
+	}
+	defer f.Close()
+
+	stat, err := parseCPUStat(f)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	prev := c.prev
+	c.prev = stat
+	if prev == nil {
+		return nil
+	}
+
+	usage, ok := cpuUsage(prev.total, stat.total)
+	if !ok {
+		return nil
+	}
+	if c.perCore {
+		for _, core := range stat.order {
+			before, found := prev.cores[core]
+			if !found {
+				continue
+			}
+			if coreUsage, ok := cpuUsage(before, stat.cores[core]); ok {
+				usage.Cores = append(usage.Cores, entity.CPUCoreUsage{
+					Core:    core,
+					User:    coreUsage.User,
+					System:  coreUsage.System,
+					Idle:    coreUsage.Idle,
+					Nice:    coreUsage.Nice,
+					IOWait:  coreUsage.IOWait,
+					IRQ:     coreUsage.IRQ,
+					SoftIRQ: coreUsage.SoftIRQ,
+					Steal:   coreUsage.Steal,
+				})
+			}
+		}
+	}
+
+	m.CPU = usage
+	m.CPUUsagePercent = 100 - usage.Idle - usage.IOWait
+	return nil
+}
+
+func parseCPUStat(r io.Reader) (*cpuStat, error) {
+	stat := &cpuStat{cores: make(map[string]cpuTimes)}
+	found := false
+
+	scanner := bufio.NewScanner(r)
+	// The intr line has a counter per interrupt number, which takes more than
+	// the default 64 KiB on large machines.
+	scanner.Buffer(nil, maxStatLine)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		times, err := parseCPUTimes(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fields[0], err)
+		}
+		if fields[0] == "cpu" {
+			stat.total = times
+			found = true
+			continue
+		}
+		stat.cores[fields[0]] = times
+		stat.order = append(stat.order, fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("aggregate cpu line not found")
+	}
+	return stat, nil
+}
+
+// parseCPUTimes parses the counters of a cpu line. Kernels before 2.6.11 do not
+// report all of them, the missing ones are left zero.
+func parseCPUTimes(fields []string) (cpuTimes, error) {
+	if len(fields) < 4 {
+		return cpuTimes{}, fmt.Errorf("expected at least 4 counters, got %d", len(fields))
+	}
+
+	var values [8]uint64
+	for idx := 0; idx < len(values) && idx < len(fields); idx++ {
+		v, err := strconv.ParseUint(fields[idx], 10, 64)
+		if err != nil {
+			return cpuTimes{}, fmt.Errorf("counter %q: %w", fields[idx], err)
+		}
+		values[idx] = v
+	}
+
+	return cpuTimes{
+		user:    values[0],
+		nice:    values[1],
+		system:  values[2],
+		idle:    values[3],
+		iowait:  values[4],
+		irq:     values[5],
+		softirq: values[6],
+		steal:   values[7],
+	}, nil
+}
+
+// cpuUsage converts counter deltas into percentages. It reports false when the
+// counters did not advance or went backwards (e.g. a CPU was brought online).
+func cpuUsage(prev, cur cpuTimes) (*entity.CPUUsage, bool) {
+	if cur.total() <= prev.total() {
+		return nil, false
+	}
+	total := float64(cur.total() - prev.total())
+	percent := func(before, after uint64) float64 {
+		if after < before {
+			return 0
+		}
+		return float64(after-before) / total * 100
+	}
+
+	return &entity.CPUUsage{
+		User:    percent(prev.user, cur.user),
+		System:  percent(prev.system, cur.system),
+		Idle:    percent(prev.idle, cur.idle),
+		Nice:    percent(prev.nice, cur.nice),
+		IOWait:  percent(prev.iowait, cur.iowait),
+		IRQ:     percent(prev.irq, cur.irq),
+		SoftIRQ: percent(prev.softirq, cur.softirq),
+		Steal:   percent(prev.steal, cur.steal),
+	}, true
+}
@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUStat(t *testing.T) {
+	data := `cpu  100 10 50 800 20 5 5 10 0 0
+cpu0 60 10 30 380 10 5 5 0 0 0
+cpu1 40 0 20 420 10 0 0 10 0 0
+intr 12345
+ctxt 67890
+`
+	stat, err := parseCPUStat(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, cpuTimes{
+		user: 100, nice: 10, system: 50, idle: 800, iowait: 20, irq: 5, softirq: 5, steal: 10,
+	}, stat.total)
+	require.Equal(t, []string{"cpu0", "cpu1"}, stat.order)
+
+	_, err = parseCPUStat(strings.NewReader("cpu0 1 2 3 4\n"))
+	require.Error(t, err)
+
+	_, err = parseCPUStat(strings.NewReader("cpu 1 2 x 4\n"))
+	require.Error(t, err)
+}
+
+func TestParseCPUStat_OldKernel(t *testing.T) {
+	stat, err := parseCPUStat(strings.NewReader("cpu 1 2 3 4\n"))
+	require.NoError(t, err)
+	require.Equal(t, cpuTimes{user: 1, nice: 2, system: 3, idle: 4}, stat.total)
+}
+
+func TestCPUUsage(t *testing.T) {
+	prev := cpuTimes{user: 100, nice: 10, system: 50, idle: 800, iowait: 20, irq: 5, softirq: 5, steal: 10}
+	cur := cpuTimes{user: 130, nice: 10, system: 60, idle: 840, iowait: 30, irq: 5, softirq: 5, steal: 20}
+
+	usage, ok := cpuUsage(prev, cur)
+	require.True(t, ok)
+	require.Equal(t, &entity.CPUUsage{User: 30, System: 10, Idle: 40, IOWait: 10, Steal: 10}, usage)
+
+	_, ok = cpuUsage(cur, cur)
+	require.False(t, ok)
+
+	_, ok = cpuUsage(cur, prev)
+	require.False(t, ok)
+}
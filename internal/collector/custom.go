@@ -0,0 +1,177 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+// outputParser turns the output of a command into values.
+type outputParser func(output string) ([]entity.CustomValue, error)
+
+var outputParsers = map[string]outputParser{
+	"":         parseNumber,
+	"number":   parseNumber,
+	"keyvalue": parseKeyValues,
+	"json":     parseJSONValues,
+}
+
+// customCollector reports a metric measured by a command configured by the
+// user. A failed run is reported in the metric rather than by Collect, so it
+// does not hide the other custom metrics.
+type customCollector struct {
+	name    string
+	command *commandCollector
+	parse   outputParser
+}
+
+func newCustomCollector(cfg config.CustomMetric) (*customCollector, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("custom metric without a name")
+	}
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("custom metric %s: no command", cfg.Name)
+	}
+	if cfg.Shell && len(cfg.Args) > 0 {
+		return nil, fmt.Errorf("custom metric %s: args cannot be used with shell", cfg.Name)
+	}
+	parse, ok := outputParsers[cfg.Output]
+	if !ok {
+		return nil, fmt.Errorf("custom metric %s: unknown output %q", cfg.Name, cfg.Output)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = cfg.Interval()
+	}
+	if timeout > cfg.Interval() {
+		return nil, fmt.Errorf("custom metric %s: timeout %s exceeds the period %s", cfg.Name, timeout, cfg.Interval())
+	}
+
+	return &customCollector{
+		name:    cfg.Name,
+		command: newCommandCollector(cfg.Command, cfg.Args, cfg.Shell, timeout),
+		parse:   parse,
+	}, nil
+}
+
+func (c *customCollector) Name() string {
+	return string(entity.CategoryCustom) + ":" + c.name
+}
+
+func (c *customCollector) Category() entity.Category {
+	return entity.CategoryCustom
+}
+
+func (c *customCollector) Available() error {
+	return nil
+}
+
+func (c *customCollector) Collect(ctx context.Context, m *entity.SystemMetrics) error {
+	result, err := c.command.Collect(ctx)
+	metric := entity.CustomMetric{
+		Name:     c.name,
+		State:    entity.CollectionOK,
+		ExitCode: result.exitCode,
+		Stderr:   result.stderr,
+	}
+	if err == nil {
+		metric.Values, err = c.parse(result.stdout)
+		if err != nil {
+			err = fmt.Errorf("parse output: %w", err)
+		}
+	}
+	if err != nil {
+		metric.State, metric.Message, metric.Values = entity.CollectionError, err.Error(), nil
+	}
+	m.Custom = append(m.Custom, metric)
+	return nil
+}
+
+func parseNumber(output string) ([]entity.CustomValue, error) {
+	value, err := strconv.ParseFloat(output, 64)
+	if err != nil {
+		return nil, fmt.Errorf("not a number: %q", output)
+	}
+	return []entity.CustomValue{{Value: value}}, nil
+}
+
+// parseKeyValues reads key=value lines, blank lines are skipped.
+func parseKeyValues(output string) ([]entity.CustomValue, error) {
+	var values []entity.CustomValue
+	for idx, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: not a key=value pair", idx+1)
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s is not a number", idx+1, key)
+		}
+		values = append(values, entity.CustomValue{Key: key, Value: number})
+	}
+	return values, nil
+}
+
+// parseJSONValues reads a number or an object. The numbers of nested objects
+// are keyed by their path joined with dots, booleans count as 0 or 1. Other
+// values, such as strings and arrays, are skipped.
+func parseJSONValues(output string) ([]entity.CustomValue, error) {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	switch document := document.(type) {
+	case json.Number:
+		value, err := document.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return []entity.CustomValue{{Value: value}}, nil
+	case map[string]any:
+		return flattenJSON("", document, nil), nil
+	default:
+		return nil, errors.New("expected an object or a number")
+	}
+}
+
+func flattenJSON(prefix string, object map[string]any, values []entity.CustomValue) []entity.CustomValue {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		switch value := object[key].(type) {
+		case json.Number:
+			if number, err := value.Float64(); err == nil {
+				values = append(values, entity.CustomValue{Key: path, Value: number})
+			}
+		case bool:
+			number := 0.0
+			if value {
+				number = 1
+			}
+			values = append(values, entity.CustomValue{Key: path, Value: number})
+		case map[string]any:
+			values = flattenJSON(path+".", value, values)
+		}
+	}
+	return values
+}
@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []entity.CustomValue
+		wantErr bool
+	}{
+		{name: "number", output: "42.5", want: []entity.CustomValue{{Value: 42.5}}},
+		{name: "number", output: "42 items", wantErr: true},
+		{
+			name:   "keyvalue",
+			output: "active = 12\n\ndeferred=3\n",
+			want:   []entity.CustomValue{{Key: "active", Value: 12}, {Key: "deferred", Value: 3}},
+		},
+		{name: "keyvalue", output: "active: 12", wantErr: true},
+		{name: "keyvalue", output: "active=many", wantErr: true},
+		{
+			name:   "json",
+			output: `{"queue": {"active": 12, "deferred": 3}, "healthy": true, "host": "mx1", "ids": [1, 2], "age": 1.5e3}`,
+			want: []entity.CustomValue{
+				{Key: "age", Value: 1500},
+				{Key: "healthy", Value: 1},
+				{Key: "queue.active", Value: 12},
+				{Key: "queue.deferred", Value: 3},
+			},
+		},
+		{name: "json", output: "7", want: []entity.CustomValue{{Value: 7}}},
+		{name: "json", output: `["a"]`, wantErr: true},
+		{name: "json", output: `{"a": `, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := outputParsers[tt.name](tt.output)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestCustomCollector(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric config.CustomMetric
+		want   entity.CustomMetric
+	}{
+		{
+			name:   "program",
+			metric: config.CustomMetric{Command: "echo", Args: []string{"42"}},
+			want:   entity.CustomMetric{Values: []entity.CustomValue{{Value: 42}}, State: entity.CollectionOK},
+		},
+		{
+			name:   "shell",
+			metric: config.CustomMetric{Command: "printf 'a=1\\nb=2\\n'", Shell: true, Output: "keyvalue"},
+			want: entity.CustomMetric{
+				Values: []entity.CustomValue{{Key: "a", Value: 1}, {Key: "b", Value: 2}},
+				State:  entity.CollectionOK,
+			},
+		},
+		{
+			name:   "no shell by default",
+			metric: config.CustomMetric{Command: "echo 1 | wc -l"},
+			want: entity.CustomMetric{
+				State:    entity.CollectionError,
+				Message:  `exec: "echo 1 | wc -l": executable file not found in $PATH`,
+				ExitCode: -1,
+			},
+		},
+		{
+			name:   "exit code",
+			metric: config.CustomMetric{Command: "echo 5; echo 'queue is locked' >&2; exit 3", Shell: true},
+			want: entity.CustomMetric{
+				State:    entity.CollectionError,
+				Message:  "exit status 3",
+				ExitCode: 3,
+				Stderr:   "queue is locked",
+			},
+		},
+		{
+			name:   "timeout",
+			metric: config.CustomMetric{Command: "sleep", Args: []string{"5"}, Timeout: 50 * time.Millisecond},
+			want: entity.CustomMetric{
+				State:    entity.CollectionError,
+				Message:  "timed out after 50ms",
+				ExitCode: -1,
+			},
+		},
+		{
+			name:   "bad output",
+			metric: config.CustomMetric{Command: "echo", Args: []string{"ready"}},
+			want: entity.CustomMetric{
+				State:   entity.CollectionError,
+				Message: `parse output: not a number: "ready"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.metric.Name, tt.want.Name = "test", "test"
+			c, err := newCustomCollector(tt.metric)
+			require.NoError(t, err)
+
+			m := &entity.SystemMetrics{}
+			require.NoError(t, c.Collect(context.Background(), m))
+			require.Equal(t, []entity.CustomMetric{tt.want}, m.Custom)
+		})
+	}
+}
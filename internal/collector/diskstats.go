@@ -0,0 +1,197 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const diskSectorSize = 512
+
+// The fastest a disk counter is taken to advance, to tell its 32-bit wraps
+// from resets: a million transfers and 2 GiB per second.
+const (
+	maxDiskTransfersPerSec = 1e6
+	maxDiskSectorsPerSec   = 2 << 30 / diskSectorSize
+)
+
+type diskCounters struct {
+	reads          uint64
+	sectorsRead    uint64
+	writes         uint64
+	sectorsWritten uint64
+}
+
+type diskSample struct {
+	device   string
+	counters diskCounters
+}
+
+type diskCollector struct {
+	path     string
+	filter   *nameFilter
+	prev     map[string]diskCounters
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newDiskCollector(procRoot string, filter *nameFilter) *diskCollector {
+	return &diskCollector{path: filepath.Join(procRoot, "diskstats"), filter: filter, now: time.Now}
+}
+
+func (c *diskCollector) Name() string {
+	return string(entity.CategoryDisk)
+}
+
+func (c *diskCollector) ReportsRates() {}
+
+func (c *diskCollector) Available() error {
+	return fileAvailable(c.path)
+}
+
+// Collect reports per-device transfers and throughput between the previous
+// and the current call. The first call only remembers the counters.
+func (c *diskCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", c.path, err)
+	}
+	defer f.Close()
+
+	now := c.now()
+	samples, err := parseDiskStats(f)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = make(map[string]diskCounters, len(samples)), now
+
+	disks := make([]entity.DiskStats, 0, len(samples))
+	for _, s := range samples {
+		if !c.filter.Match(s.device) {
+			continue
+		}
+		c.prev[s.device] = s.counters
+
+		before, ok := prev[s.device]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		if stats, ok := diskRates(s.device, before, s.counters, elapsed); ok {
+			disks = append(disks, stats)
+		}
+	}
+
+	if prev != nil {
+		m.Disks = disks
+	}
+	return nil
+}
+
+// diskRates reports false when a counter was reset, as when the device was
+// removed and plugged in again.
+func diskRates(device string, prev, cur diskCounters, elapsed float64) (entity.DiskStats, bool) {
+	deltas := counterDeltas{elapsed: elapsed}
+	transfers := deltas.add(prev.reads, cur.reads, maxDiskTransfersPerSec) +
+		deltas.add(prev.writes, cur.writes, maxDiskTransfersPerSec)
+	sectors := deltas.add(prev.sectorsRead, cur.sectorsRead, maxDiskSectorsPerSec) +
+		deltas.add(prev.sectorsWritten, cur.sectorsWritten, maxDiskSectorsPerSec)
+
+	return entity.DiskStats{
+		Device:   device,
+		TPS:      float64(transfers) / elapsed,
+		KBPerSec: float64(sectors) * diskSectorSize / 1024 / elapsed,
+	}, !deltas.reset
+}
+
+// counterDelta returns the increase of a kernel counter between two readings
+// taken elapsed seconds apart. Counters kept in an "unsigned long" wrap at 2^32
+// on 32-bit kernels. A decrease is taken for such a wrap when both readings
+// fit in 32 bits and the wrapped increase is plausible: the counter advances
+// at most maxRate per second, and less than half its range between readings,
+// beyond which a wrap cannot be told from a reset. Otherwise the counter was
+// reset, e.g. with its device, and ok is false: the sample is to be dropped.
+// A maxRate of zero marks the counters that are 64 bits wide everywhere.
+func counterDelta(prev, cur uint64, elapsed, maxRate float64) (delta uint64, ok bool) {
+	if cur >= prev {
+		return cur - prev, true
+	}
+	if prev > math.MaxUint32 {
+		return 0, false
+	}
+	delta = math.MaxUint32 - prev + cur + 1
+	if delta > math.MaxUint32/2 || float64(delta) > maxRate*elapsed {
+		return 0, false
+	}
+	return delta, true
+}
+
+// counterDeltas computes the increases of several counters of one sample and
+// remembers whether any of them was reset.
+type counterDeltas struct {
+	elapsed float64
+	reset   bool
+}
+
+func (d *counterDeltas) add(prev, cur uint64, maxRate float64) uint64 {
+	delta, ok := counterDelta(prev, cur, d.elapsed, maxRate)
+	d.reset = d.reset || !ok
+	return delta
+}
+
+// parseDiskStats parses /proc/diskstats. Besides the modern format (14, 18 or
+// 20 fields) it accepts the 7-field partition lines of 2.6 kernels.
+func parseDiskStats(r io.Reader) ([]diskSample, error) {
+	var samples []diskSample
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var idx [4]int
+		switch {
+		case len(fields) >= 14:
+			idx = [4]int{3, 5, 7, 9}
+		case len(fields) == 7:
+			idx = [4]int{3, 4, 5, 6}
+		default:
+			return nil, fmt.Errorf("unexpected number of fields (%d): %q", len(fields), scanner.Text())
+		}
+
+		var values [4]uint64
+		for n, i := range idx {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: counter %q: %w", fields[2], fields[i], err)
+			}
+			values[n] = v
+		}
+
+		samples = append(samples, diskSample{
+			device: fields[2],
+			counters: diskCounters{
+				reads:          values[0],
+				sectorsRead:    values[1],
+				writes:         values[2],
+				sectorsWritten: values[3],
+			},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
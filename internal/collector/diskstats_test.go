@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiskStats(t *testing.T) {
+	data := `   8       0 sda 1000 10 20000 500 2000 20 40000 900 0 1200 1400 0 0 0 0 0 0
+   8       1 sda1 900 10 18000 450 1900 20 38000 850 0 1100 1300
+   3       1 hda1 120 2400 80 1600
+`
+	samples, err := parseDiskStats(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, []diskSample{
+		{device: "sda", counters: diskCounters{reads: 1000, sectorsRead: 20000, writes: 2000, sectorsWritten: 40000}},
+		{device: "sda1", counters: diskCounters{reads: 900, sectorsRead: 18000, writes: 1900, sectorsWritten: 38000}},
+		{device: "hda1", counters: diskCounters{reads: 120, sectorsRead: 2400, writes: 80, sectorsWritten: 1600}},
+	}, samples)
+
+	_, err = parseDiskStats(strings.NewReader("8 0 sda 1 2 3\n"))
+	require.Error(t, err)
+}
+
+func TestDiskRates(t *testing.T) {
+	prev := diskCounters{reads: 100, sectorsRead: 1000, writes: 50, sectorsWritten: 2000}
+	cur := diskCounters{reads: 120, sectorsRead: 3048, writes: 70, sectorsWritten: 4000}
+
+	stats, ok := diskRates("sda", prev, cur, 2)
+	require.True(t, ok)
+	require.Equal(t, entity.DiskStats{Device: "sda", TPS: 20, KBPerSec: 1012}, stats)
+
+	// The device was plugged in again, its counters started over.
+	_, ok = diskRates("sda", cur, diskCounters{reads: 3, sectorsRead: 48}, 2)
+	require.False(t, ok)
+
+	// The counters of a 32-bit kernel wrapped.
+	wrapped := diskCounters{reads: math.MaxUint32 - 9, sectorsRead: math.MaxUint32 - 1023, writes: 50, sectorsWritten: 2000}
+	stats, ok = diskRates("sda", wrapped, diskCounters{reads: 10, sectorsRead: 1024, writes: 50, sectorsWritten: 2000}, 2)
+	require.True(t, ok)
+	require.Equal(t, entity.DiskStats{Device: "sda", TPS: 10, KBPerSec: 512}, stats)
+}
+
+func TestCounterDelta(t *testing.T) {
+	delta, ok := counterDelta(5, 15, 1, 0)
+	require.True(t, ok)
+	require.Equal(t, uint64(10), delta)
+
+	// A 32-bit counter wrapped.
+	delta, ok = counterDelta(math.MaxUint32-5, 10, 1, 100)
+	require.True(t, ok)
+	require.Equal(t, uint64(16), delta)
+
+	for _, tt := range []struct {
+		name             string
+		prev, cur        uint64
+		elapsed, maxRate float64
+	}{
+		{"64-bit counter", math.MaxUint32 - 5, 10, 1, 0},
+		{"beyond 32 bits", math.MaxUint64 - 5, 10, 1, 100},
+		{"faster than the counter advances", math.MaxUint32 - 500, 10, 2, 100},
+		{"more than half the range", math.MaxUint32 / 4, 10, 1, math.MaxUint32},
+	} {
+		_, ok = counterDelta(tt.prev, tt.cur, tt.elapsed, tt.maxRate)
+		require.False(t, ok, tt.name)
+	}
+}
+
+func TestDiskFilter_Defaults(t *testing.T) {
+	filter, err := newNameFilter(nil, config.DiskCollector{}.ExcludePatterns())
+	require.NoError(t, err)
+
+	for _, device := range []string{"sda", "vda", "nvme0n1", "mmcblk0", "dm-0", "md0"} {
+		require.True(t, filter.Match(device), device)
+	}
+	for _, device := range []string{"sda1", "vdb2", "nvme0n1p1", "mmcblk0p2", "loop0", "ram15", "zram0", "sr0"} {
+		require.False(t, filter.Match(device), device)
+	}
+}
+
+func TestNameFilter(t *testing.T) {
+	filter, err := newNameFilter([]string{"^sd"}, []string{"^sdb$"})
+	require.NoError(t, err)
+
+	require.True(t, filter.Match("sda"))
+	require.False(t, filter.Match("sdb"))
+	require.False(t, filter.Match("vda"))
+
+	_, err = newNameFilter([]string{"("}, nil)
+	require.Error(t, err)
+}
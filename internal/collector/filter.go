@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// nameFilter selects names (devices, interfaces...) by regular expressions.
+// A name passes when it matches any include pattern, or the include list is
+// empty, and matches none of the exclude patterns.
+type nameFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newNameFilter(include, exclude []string) (*nameFilter, error) {
+	f := &nameFilter{}
+	for _, p := range include {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("include pattern %q: %w", p, err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, p := range exclude {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("exclude pattern %q: %w", p, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+func (f *nameFilter) Match(name string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, name) {
+		return false
+	}
+	return !matchAny(f.exclude, name)
+}
+
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const defaultStatfsTimeout = time.Second
+
+type mountInfo struct {
+	mountPoint string
+	fsType     string
+}
+
+type fsStat struct {
+	blocks, bfree, bavail uint64
+	files, ffree          uint64
+	blockSize             uint64
+}
+
+type fsCollector struct {
+	path         string
+	hostRoot     string
+	includeTypes map[string]struct{}
+	excludeTypes map[string]struct{}
+	timeout      time.Duration
+	stat         func(path string) (fsStat, error)
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// newFsCollector lists the mounts of the daemon itself, unless the host's root
+// is mounted elsewhere: then it lists the mounts of the host's init process and
+// queries them under hostRoot.
+func newFsCollector(procRoot, hostRoot string, includeTypes, excludeTypes []string, timeout time.Duration) *fsCollector {
+	if timeout <= 0 {
+		timeout = defaultStatfsTimeout
+	}
+	process := "self"
+	if filepath.Clean(hostRoot) != "/" {
+		process = "1"
+	}
+	return &fsCollector{
+		path:         filepath.Join(procRoot, process, "mountinfo"),
+		hostRoot:     hostRoot,
+		includeTypes: toSet(includeTypes),
+		excludeTypes: toSet(excludeTypes),
+		timeout:      timeout,
+		stat:         statfs,
+		pending:      make(map[string]struct{}),
+	}
+}
+
+func (c *fsCollector) Name() string {
+	return string(entity.CategoryFilesystem)
+}
+
+func (c *fsCollector) Available() error {
+	return fileAvailable(c.path)
+}
+
+func (c *fsCollector) Collect(ctx context.Context, m *entity.SystemMetrics) error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", c.path, err)
+	}
+	defer f.Close()
+
+	mounts, err := parseMountInfo(f)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	filesystems := make([]entity.FsUsage, 0, len(mounts))
+	for _, mount := range mounts {
+		if !c.match(mount.fsType) {
+			continue
+		}
+
+		stat, ok := c.statfs(ctx, filepath.Join(c.hostRoot, mount.mountPoint))
+		if !ok || stat.blocks == 0 {
+			continue
+		}
+
+		usage := fsUsage(mount.mountPoint, stat)
+		if usage.MountPoint == "/" {
+			m.DiskUsedPercent = usage.UsedPercent
+		}
+		filesystems = append(filesystems, usage)
+	}
+
+	m.Filesystems = filesystems
+	return nil
+}
+
+func (c *fsCollector) match(fsType string) bool {
+	if len(c.includeTypes) > 0 {
+		if _, ok := c.includeTypes[fsType]; !ok {
+			return false
+		}
+	}
+	_, excluded := c.excludeTypes[fsType]
+	return !excluded
+}
+
+// statfs queries a mount point without blocking for longer than the timeout.
+// A call stuck on a hung network mount keeps running in the background, and
+// the mount point is skipped until it returns.
+func (c *fsCollector) statfs(ctx context.Context, mountPoint string) (fsStat, bool) {
+	c.mu.Lock()
+	if _, ok := c.pending[mountPoint]; ok {
+		c.mu.Unlock()
+		return fsStat{}, false
+	}
+	c.pending[mountPoint] = struct{}{}
+	c.mu.Unlock()
+
+	type result struct {
+		stat fsStat
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		stat, err := c.stat(mountPoint)
+		c.mu.Lock()
+		delete(c.pending, mountPoint)
+		c.mu.Unlock()
+		done <- result{stat: stat, err: err}
+	}()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.stat, r.err == nil
+	case <-timer.C:
+		return fsStat{}, false
+	case <-ctx.Done():
+		return fsStat{}, false
+	}
+}
+
+// fsUsage computes the usage the way df does: the used percentage is relative
+// to the space available to unprivileged users.
+func fsUsage(mountPoint string, s fsStat) entity.FsUsage {
+	const mb = 1024 * 1024
+
+	used := s.blocks - s.bfree
+	usage := entity.FsUsage{
+		MountPoint: mountPoint,
+		TotalMB:    s.blocks * s.blockSize / mb,
+		UsedMB:     used * s.blockSize / mb,
+	}
+	if avail := used + s.bavail; avail > 0 {
+		usage.UsedPercent = float64(used) / float64(avail) * 100
+	}
+	if s.files > 0 {
+		usage.InodesTotal = s.files
+		usage.InodesUsed = s.files - s.ffree
+		usage.InodesUsedPercent = float64(usage.InodesUsed) / float64(s.files) * 100
+	}
+	return usage
+}
+
+// parseMountInfo parses /proc/self/mountinfo. When a mount point is mounted
+// several times only the last (visible) mount is kept.
+func parseMountInfo(r io.Reader) ([]mountInfo, error) {
+	var mounts []mountInfo
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		sep := -1
+		for idx := 6; idx < len(fields); idx++ {
+			if fields[idx] == "-" {
+				sep = idx
+				break
+			}
+		}
+		if len(fields) < 7 || sep < 0 || sep+1 >= len(fields) {
+			return nil, fmt.Errorf("unexpected format: %q", line)
+		}
+
+		mount := mountInfo{
+			mountPoint: unescapeMountPath(fields[4]),
+			fsType:     fields[sep+1],
+		}
+		if idx, ok := index[mount.mountPoint]; ok {
+			mounts[idx] = mount
+			continue
+		}
+		index[mount.mountPoint] = len(mounts)
+		mounts = append(mounts, mount)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// unescapeMountPath decodes the octal escapes (\040 for a space, etc.) the
+// kernel uses in mount paths.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] == '\\' && idx+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[idx+1:idx+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				idx += 3
+				continue
+			}
+		}
+		b.WriteByte(s[idx])
+	}
+	return b.String()
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMountInfo(t *testing.T) {
+	data := `23 28 0:22 / /proc rw,relatime - proc proc rw
+28 1 253:1 / / rw,relatime shared:1 - ext4 /dev/vda1 rw
+40 28 253:2 / /mnt/my\040disk rw,relatime shared:20 master:1 - xfs /dev/vdb rw
+41 28 0:40 / /mnt/my\040disk rw,relatime - nfs4 server:/export rw
+`
+	mounts, err := parseMountInfo(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, []mountInfo{
+		{mountPoint: "/proc", fsType: "proc"},
+		{mountPoint: "/", fsType: "ext4"},
+		{mountPoint: "/mnt/my disk", fsType: "nfs4"},
+	}, mounts)
+
+	_, err = parseMountInfo(strings.NewReader("28 1 253:1 / / rw,relatime ext4 /dev/vda1 rw\n"))
+	require.Error(t, err)
+}
+
+func TestFsUsage(t *testing.T) {
+	stat := fsStat{
+		blocks: 2621440, bfree: 1310720, bavail: 1179648,
+		files: 655360, ffree: 589824, blockSize: 4096,
+	}
+
+	usage := fsUsage("/", stat)
+	require.Equal(t, uint64(10240), usage.TotalMB)
+	require.Equal(t, uint64(5120), usage.UsedMB)
+	require.InDelta(t, 52.63, usage.UsedPercent, 0.01)
+	require.Equal(t, uint64(655360), usage.InodesTotal)
+	require.Equal(t, uint64(65536), usage.InodesUsed)
+	require.InDelta(t, 10, usage.InodesUsedPercent, 0.001)
+
+	noInodes := fsUsage("/btrfs", fsStat{blocks: 100, bfree: 50, bavail: 50, blockSize: 4096})
+	require.Equal(t, entity.FsUsage{MountPoint: "/btrfs", TotalMB: 0, UsedMB: 0, UsedPercent: 50}, noInodes)
+}
+
+func TestFsCollector_Match(t *testing.T) {
+	c := newFsCollector("/proc", "/", nil, []string{"proc", "tmpfs"}, 0)
+	require.True(t, c.match("ext4"))
+	require.False(t, c.match("tmpfs"))
+
+	c = newFsCollector("/proc", "/", []string{"ext4", "xfs"}, nil, 0)
+	require.True(t, c.match("xfs"))
+	require.False(t, c.match("nfs4"))
+}
@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/hosts")
+
+// hostGolden is what the collectors report for a host of testdata/hosts.
+type hostGolden struct {
+	Metrics *entity.SystemMetrics
+	// Unavailable lists the categories the host has no files for.
+	Unavailable []string `json:",omitempty"`
+}
+
+// TestHosts runs every procfs collector against snapshots of real hosts. Each
+// host of testdata/hosts has two snapshots taken 5 seconds apart, start and
+// end, and the expected metrics in golden.json. Statfs and the clock are
+// replaced, as they do not come from the snapshots. The host is mounted away
+// from /, so its mounts and network tables are those of proc/1. After an
+// intended change of the output, rewrite the golden files with:
+//
+//	go test ./internal/collector -run TestHosts -update
+func TestHosts(t *testing.T) {
+	hosts, err := os.ReadDir(filepath.Join("testdata", "hosts"))
+	require.NoError(t, err)
+	require.NotEmpty(t, hosts)
+
+	for _, host := range hosts {
+		t.Run(host.Name(), func(t *testing.T) {
+			dir, err := filepath.Abs(filepath.Join("testdata", "hosts", host.Name()))
+			require.NoError(t, err)
+			// The collectors read through a link that is moved from the first
+			// snapshot to the second one.
+			root := filepath.Join(t.TempDir(), "host")
+			require.NoError(t, os.Symlink(filepath.Join(dir, "start"), root))
+
+			collectors, err := New(config.Collectors{
+				Host:    config.Host{Proc: filepath.Join(root, "proc"), Sys: filepath.Join(root, "sys"), Root: root},
+				CPU:     config.CPUCollector{PerCore: true},
+				Cgroups: config.CgroupsCollector{Top: 3},
+			})
+			require.NoError(t, err)
+
+			now := time.Unix(1700000000, 0)
+			golden := hostGolden{Metrics: &entity.SystemMetrics{}}
+			var available []Scheduled
+			for _, c := range collectors {
+				switch c := c.Collector.(type) {
+				case *diskCollector:
+					c.now = func() time.Time { return now }
+				case *interfacesCollector:
+					c.now = func() time.Time { return now }
+				case *pressureCollector:
+					c.now = func() time.Time { return now }
+				case *cgroupsCollector:
+					c.now = func() time.Time { return now }
+				case *protocolsCollector:
+					c.now = func() time.Time { return now }
+				case *memoryCollector:
+					c.now = func() time.Time { return now }
+					c.pageSize = 4096
+				case *fsCollector:
+					c.stat = fixedStatfs
+				}
+				if c.Available() != nil {
+					golden.Unavailable = append(golden.Unavailable, c.Name())
+					continue
+				}
+				available = append(available, c)
+			}
+
+			for _, c := range available {
+				require.NoError(t, c.Collect(context.Background(), &entity.SystemMetrics{}), c.Name())
+			}
+			now = now.Add(5 * time.Second)
+			require.NoError(t, os.Remove(root))
+			require.NoError(t, os.Symlink(filepath.Join(dir, "end"), root))
+			for _, c := range available {
+				require.NoError(t, c.Collect(context.Background(), golden.Metrics), c.Name())
+			}
+
+			got, err := json.MarshalIndent(golden, "", "  ")
+			require.NoError(t, err)
+			path := filepath.Join(dir, "golden.json")
+			if *update {
+				require.NoError(t, os.WriteFile(path, append(got, '\n'), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+// fixedStatfs reports a 100 GiB filesystem, a quarter of it used.
+func fixedStatfs(string) (fsStat, error) {
+	return fsStat{
+		blocks: 26214400, bfree: 19660800, bavail: 18350080,
+		files: 6553600, ffree: 6291456, blockSize: 4096,
+	}, nil
+}
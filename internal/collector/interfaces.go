@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const unknownOperState = "unknown"
+
+type interfacesCollector struct {
+	path     string
+	sysNet   string
+	filter   *nameFilter
+	prev     map[string]netDevStats
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newInterfacesCollector(procRoot, sysRoot string, filter *nameFilter) *interfacesCollector {
+	return &interfacesCollector{
+		path:   filepath.Join(netDir(procRoot), "dev"),
+		sysNet: filepath.Join(sysRoot, "class", "net"),
+		filter: filter,
+		now:    time.Now,
+	}
+}
+
+func (c *interfacesCollector) Name() string {
+	return string(entity.CategoryInterfaces)
+}
+
+func (c *interfacesCollector) ReportsRates() {}
+
+func (c *interfacesCollector) Available() error {
+	return fileAvailable(c.path)
+}
+
+// Collect reports per-interface traffic between the previous and the current
+// call. The first call only remembers the counters.
+func (c *interfacesCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", c.path, err)
+	}
+	defer f.Close()
+
+	now := c.now()
+	devices, err := parseNetDev(f)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = make(map[string]netDevStats, len(devices)), now
+
+	interfaces := make([]entity.InterfaceStats, 0, len(devices))
+	for _, d := range devices {
+		if !c.filter.Match(d.name) {
+			continue
+		}
+		c.prev[d.name] = d.netDevStats
+
+		before, ok := prev[d.name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		stats, ok := interfaceRates(d.name, before, d.netDevStats, elapsed)
+		if !ok {
+			continue
+		}
+		stats.State, stats.SpeedMbps = c.link(d.name)
+		interfaces = append(interfaces, stats)
+	}
+
+	if prev != nil {
+		m.Interfaces = interfaces
+	}
+	return nil
+}
+
+// link reads the operational state and the speed of an interface from sysfs.
+// Virtual interfaces report no speed (or -1), and /sys may not be mounted at
+// all; the state is "unknown" and the speed is zero then.
+func (c *interfacesCollector) link(name string) (string, uint64) {
+	state := unknownOperState
+	if data, err := os.ReadFile(filepath.Join(c.sysNet, name, "operstate")); err == nil {
+		if s := string(bytes.TrimSpace(data)); s != "" {
+			state = s
+		}
+	}
+
+	var speed uint64
+	if data, err := os.ReadFile(filepath.Join(c.sysNet, name, "speed")); err == nil {
+		if v, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64); err == nil && v > 0 {
+			speed = uint64(v)
+		}
+	}
+	return state, speed
+}
+
+// interfaceRates reports false when a counter was reset, as when the interface
+// was deleted and created again under the same name.
+func interfaceRates(name string, prev, cur netDevStats, elapsed float64) (entity.InterfaceStats, bool) {
+	deltas := counterDeltas{elapsed: elapsed}
+	rate := func(prev, cur uint64, maxRate float64) float64 {
+		return float64(deltas.add(prev, cur, maxRate)) / elapsed
+	}
+	return entity.InterfaceStats{
+		Name:            name,
+		RxBytesPerSec:   rate(prev.rxBytes, cur.rxBytes, maxLinkBytesPerSec),
+		TxBytesPerSec:   rate(prev.txBytes, cur.txBytes, maxLinkBytesPerSec),
+		RxPacketsPerSec: rate(prev.rxPackets, cur.rxPackets, maxLinkPacketsPerSec),
+		TxPacketsPerSec: rate(prev.txPackets, cur.txPackets, maxLinkPacketsPerSec),
+		RxErrorsPerSec:  rate(prev.rxErrs, cur.rxErrs, maxLinkPacketsPerSec),
+		TxErrorsPerSec:  rate(prev.txErrs, cur.txErrs, maxLinkPacketsPerSec),
+		RxDropsPerSec:   rate(prev.rxDrop, cur.rxDrop, maxLinkPacketsPerSec),
+		TxDropsPerSec:   rate(prev.txDrop, cur.txDrop, maxLinkPacketsPerSec),
+	}, !deltas.reset
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+func TestInterfacesCollector(t *testing.T) {
+	proc, sys := t.TempDir(), t.TempDir()
+	write := func(rxBytes, txDrop int) {
+		writeFile(t, filepath.Join(proc, "1", "net", "dev"), fmt.Sprintf(`Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:     100       1    0    0    0     0          0         0      100       1    0    0    0     0       0          0
+  eth0: %7d     238    1    2    0     0          0         0    37818     263    3 %4d    0     0       0          0
+  eth1:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+`, rxBytes, txDrop))
+	}
+	writeFile(t, filepath.Join(sys, "class", "net", "eth0", "operstate"), "up\n")
+	writeFile(t, filepath.Join(sys, "class", "net", "eth0", "speed"), "1000\n")
+	writeFile(t, filepath.Join(sys, "class", "net", "eth1", "operstate"), "down\n")
+	writeFile(t, filepath.Join(sys, "class", "net", "eth1", "speed"), "-1\n")
+
+	filter, err := newNameFilter(nil, []string{"^lo$"})
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	c := newInterfacesCollector(proc, sys, filter)
+	c.now = func() time.Time { return now }
+
+	write(470408, 4)
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Nil(t, m.Interfaces)
+
+	write(490408, 10)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.InterfaceStats{
+		{Name: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 10000, TxDropsPerSec: 3},
+		{Name: "eth1", State: "down"},
+	}, m.Interfaces)
+
+	// eth0 was created again and is left out until it has a delta.
+	write(1000, 0)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.InterfaceStats{{Name: "eth1", State: "down"}}, m.Interfaces)
+
+	write(21000, 2)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.InterfaceStats{
+		{Name: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 10000, TxDropsPerSec: 1},
+		{Name: "eth1", State: "down"},
+	}, m.Interfaces)
+}
+
+func TestInterfacesCollector_NoSysfs(t *testing.T) {
+	c := newInterfacesCollector(t.TempDir(), t.TempDir(), nil)
+
+	state, speed := c.link("eth0")
+	require.Equal(t, "unknown", state)
+	require.Zero(t, speed)
+}
+
+func TestInterfaceFilter_Defaults(t *testing.T) {
+	filter, err := newNameFilter(nil, config.InterfacesCollector{}.ExcludePatterns())
+	require.NoError(t, err)
+
+	for _, name := range []string{"lo", "eth0", "ens3", "enp65s0f0", "bond0", "wlan0", "docker-proxy"} {
+		require.True(t, filter.Match(name), name)
+	}
+	for _, name := range []string{"veth3f2a1b0", "docker0", "br-5c1e2f3a4b6d"} {
+		require.False(t, filter.Match(name), name)
+	}
+}
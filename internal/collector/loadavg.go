@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+type loadAvgCollector struct {
+	path string
+}
+
+func newLoadAvgCollector(procRoot string) *loadAvgCollector {
+	return &loadAvgCollector{path: filepath.Join(procRoot, "loadavg")}
+}
+
+func (c *loadAvgCollector) Name() string {
+	return string(entity.CategoryLoadAverage)
+}
+
+func (c *loadAvgCollector) Available() error {
+	return fileAvailable(c.path)
+}
+
+func (c *loadAvgCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", c.path, err)
+	}
+
+	loadAvg, err := parseLoadAvg(string(data))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	m.LoadAverage = loadAvg
+	return nil
+}
+
+// parseLoadAvg parses /proc/loadavg, e.g. "0.20 0.18 0.12 1/80 11206".
+func parseLoadAvg(data string) (*entity.LoadAverage, error) {
+	fields := strings.Fields(data)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("unexpected format: %q", strings.TrimSpace(data))
+	}
+
+	var loads [3]float64
+	for idx := range loads {
+		v, err := strconv.ParseFloat(fields[idx], 64)
+		if err != nil {
+			return nil, fmt.Errorf("load average %q: %w", fields[idx], err)
+		}
+		loads[idx] = v
+	}
+
+	running, total, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return nil, fmt.Errorf("tasks %q: missing '/'", fields[3])
+	}
+	runningTasks, err := strconv.ParseUint(running, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("running tasks %q: %w", running, err)
+	}
+	totalTasks, err := strconv.ParseUint(total, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("total tasks %q: %w", total, err)
+	}
+
+	return &entity.LoadAverage{
+		OneMin:       loads[0],
+		FiveMins:     loads[1],
+		FifteenMins:  loads[2],
+		RunningTasks: uint32(runningTasks),
+		TotalTasks:   uint32(totalTasks),
+	}, nil
+}
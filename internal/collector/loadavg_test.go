@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoadAvg(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected *entity.LoadAverage
+		wantErr  bool
+	}{
+		{
+			name: "Valid loadavg",
+			data: "0.20 0.18 0.12 1/80 11206\n",
+			expected: &entity.LoadAverage{
+				OneMin: 0.20, FiveMins: 0.18, FifteenMins: 0.12, RunningTasks: 1, TotalTasks: 80,
+			},
+		},
+		{name: "Empty file", data: "", wantErr: true},
+		{name: "Broken load value", data: "0.20 abc 0.12 1/80 11206", wantErr: true},
+		{name: "Missing tasks separator", data: "0.20 0.18 0.12 180 11206", wantErr: true},
+		{name: "Broken tasks", data: "0.20 0.18 0.12 1/x 11206", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadAvg, err := parseLoadAvg(tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, loadAvg)
+		})
+	}
+}
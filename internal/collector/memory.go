@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+// maxSwapPagesPerSec is the fastest the swap counters are taken to advance, to
+// tell their 32-bit wraps from resets: 4 GiB per second of 4 KiB pages.
+const maxSwapPagesPerSec = 1 << 20
+
+type swapCounters struct {
+	in  uint64
+	out uint64
+}
+
+// memoryCollector reports memory and swap usage from /proc/meminfo and the
+// swap traffic between the previous and the current call from /proc/vmstat.
+// The first call only remembers the counters.
+type memoryCollector struct {
+	meminfo  string
+	vmstat   string
+	pageSize uint64
+	prev     *swapCounters
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newMemoryCollector(procRoot string) *memoryCollector {
+	return &memoryCollector{
+		meminfo:  filepath.Join(procRoot, "meminfo"),
+		vmstat:   filepath.Join(procRoot, "vmstat"),
+		pageSize: uint64(os.Getpagesize()),
+		now:      time.Now,
+	}
+}
+
+func (c *memoryCollector) Name() string {
+	return string(entity.CategoryMemory)
+}
+
+func (c *memoryCollector) ReportsRates() {}
+
+func (c *memoryCollector) Available() error {
+	if err := fileAvailable(c.meminfo); err != nil {
+		return err
+	}
+	return fileAvailable(c.vmstat)
+}
+
+func (c *memoryCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	info, err := readNameValues(c.meminfo)
+	if err != nil {
+		return err
+	}
+	memory, err := memoryStats(info)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.meminfo, err)
+	}
+
+	vmstat, err := readNameValues(c.vmstat)
+	if err != nil {
+		return err
+	}
+	now := c.now()
+	cur := &swapCounters{in: vmstat["pswpin"], out: vmstat["pswpout"]}
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = cur, now
+	if prev == nil || elapsed <= 0 {
+		return nil
+	}
+
+	// A reset of the swap counters only leaves out the rates.
+	deltas := counterDeltas{elapsed: elapsed}
+	swapIn := deltas.add(prev.in, cur.in, maxSwapPagesPerSec)
+	swapOut := deltas.add(prev.out, cur.out, maxSwapPagesPerSec)
+	if !deltas.reset {
+		kb := float64(c.pageSize) / 1024
+		memory.SwapInKBPerSec = float64(swapIn) * kb / elapsed
+		memory.SwapOutKBPerSec = float64(swapOut) * kb / elapsed
+	}
+	m.Memory, m.MemoryUsedMB = memory, memory.UsedMB
+	return nil
+}
+
+func readNameValues(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	counters := make(map[string]uint64)
+	if err := parseNameValues(f, counters); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return counters, nil
+}
+
+// memoryStats computes the usage from /proc/meminfo as free(1) does. Kernels
+// older than 3.14 have no MemAvailable, it is then estimated as the free
+// memory and the reclaimable caches.
+func memoryStats(info map[string]uint64) (*entity.MemoryStats, error) {
+	for _, key := range []string{"MemTotal", "MemFree"} {
+		if _, ok := info[key]; !ok {
+			return nil, fmt.Errorf("no %s", key)
+		}
+	}
+
+	total, free := info["MemTotal"], info["MemFree"]
+	buffCache := info["Buffers"] + info["Cached"] + info["SReclaimable"]
+	available, ok := info["MemAvailable"]
+	if !ok {
+		available = min(free+buffCache, total)
+	}
+	used := total - free
+	if available <= total {
+		used = total - available
+	}
+
+	swapTotal := info["SwapTotal"]
+
+	const kbPerMB = 1024
+	return &entity.MemoryStats{
+		TotalMB:     total / kbPerMB,
+		AvailableMB: available / kbPerMB,
+		UsedMB:      used / kbPerMB,
+		BuffCacheMB: buffCache / kbPerMB,
+		DirtyMB:     info["Dirty"] / kbPerMB,
+		SwapTotalMB: swapTotal / kbPerMB,
+		SwapUsedMB:  (swapTotal - min(info["SwapFree"], swapTotal)) / kbPerMB,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+func TestMemoryStats(t *testing.T) {
+	tests := []struct {
+		name string
+		info map[string]uint64
+		want *entity.MemoryStats
+	}{
+		{
+			name: "MemAvailable",
+			info: map[string]uint64{
+				"MemTotal": 8 << 20, "MemFree": 1 << 20, "MemAvailable": 5 << 20,
+				"Buffers": 1 << 19, "Cached": 3 << 20, "SReclaimable": 1 << 19, "Dirty": 2048,
+				"SwapTotal": 2 << 20, "SwapFree": 3 << 19,
+			},
+			want: &entity.MemoryStats{
+				TotalMB: 8192, AvailableMB: 5120, UsedMB: 3072, BuffCacheMB: 4096, DirtyMB: 2,
+				SwapTotalMB: 2048, SwapUsedMB: 512,
+			},
+		},
+		{
+			name: "before 3.14",
+			info: map[string]uint64{"MemTotal": 2 << 20, "MemFree": 1 << 19, "Buffers": 1 << 18, "Cached": 1 << 19},
+			want: &entity.MemoryStats{TotalMB: 2048, AvailableMB: 1280, UsedMB: 768, BuffCacheMB: 768},
+		},
+		{
+			// Seen in containers whose meminfo is emulated, e.g. by lxcfs.
+			name: "available above total",
+			info: map[string]uint64{"MemTotal": 1 << 20, "MemFree": 1 << 19, "MemAvailable": 2 << 20},
+			want: &entity.MemoryStats{TotalMB: 1024, AvailableMB: 2048, UsedMB: 512},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := memoryStats(tt.info)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := memoryStats(map[string]uint64{"MemFree": 1})
+	require.ErrorContains(t, err, "MemTotal")
+}
+
+func TestMemoryCollector(t *testing.T) {
+	root := t.TempDir()
+	write := func(pswpin, pswpout int) {
+		writeFile(t, filepath.Join(root, "meminfo"), "MemTotal: 4194304 kB\nMemFree: 1048576 kB\nMemAvailable: 2097152 kB\n")
+		writeFile(t, filepath.Join(root, "vmstat"), fmt.Sprintf("pgfault 120\npswpin %d\npswpout %d\n", pswpin, pswpout))
+	}
+	now := time.Unix(1700000000, 0)
+	c := newMemoryCollector(root)
+	c.pageSize, c.now = 4096, func() time.Time { return now }
+
+	write(100, 200)
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Nil(t, m.Memory)
+
+	write(150, 200)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, &entity.MemoryStats{TotalMB: 4096, AvailableMB: 2048, UsedMB: 2048, SwapInKBPerSec: 100}, m.Memory)
+	require.Equal(t, uint64(2048), m.MemoryUsedMB)
+
+	// The swap counters went back: the gauges are still reported.
+	write(10, 20)
+	now = now.Add(2 * time.Second)
+	m = &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, &entity.MemoryStats{TotalMB: 4096, AvailableMB: 2048, UsedMB: 2048}, m.Memory)
+	require.Equal(t, uint64(2048), m.MemoryUsedMB)
+}
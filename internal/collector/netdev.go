@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The fastest a network counter is taken to advance, to tell its 32-bit wraps
+// from resets: the bytes and the smallest frames of a 10 Gbit/s link.
+const (
+	maxLinkBytesPerSec   = 10e9 / 8
+	maxLinkPacketsPerSec = 15e6
+)
+
+type netDevStats struct {
+	rxBytes, rxPackets, rxErrs, rxDrop uint64
+	txBytes, txPackets, txErrs, txDrop uint64
+}
+
+type netDevice struct {
+	name string
+	netDevStats
+}
+
+// parseNetDev parses /proc/net/dev. The device order of the file is kept.
+func parseNetDev(r io.Reader) ([]netDevice, error) {
+	var devices []netDevice
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			// The two header lines.
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("unexpected format: %q", scanner.Text())
+		}
+
+		var values [16]uint64
+		for idx := range values {
+			v, err := strconv.ParseUint(fields[idx], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: counter %q: %w", strings.TrimSpace(name), fields[idx], err)
+			}
+			values[idx] = v
+		}
+		devices = append(devices, netDevice{
+			name: strings.TrimSpace(name),
+			netDevStats: netDevStats{
+				rxBytes:   values[0],
+				rxPackets: values[1],
+				rxErrs:    values[2],
+				rxDrop:    values[3],
+				txBytes:   values[8],
+				txPackets: values[9],
+				txErrs:    values[10],
+				txDrop:    values[11],
+			},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TCP states as reported in the "st" column of /proc/net/tcp (include/net/tcp_states.h).
+const (
+	tcpListen = 0x0A
+	udpClose  = 0x07
+)
+
+// netDir returns the directory of the network tables. /proc/net links to those
+// of the daemon's own network namespace; when the host's /proc is mounted
+// elsewhere, the ones of the host's init process are read instead.
+func netDir(procRoot string) string {
+	if filepath.Clean(procRoot) != "/proc" {
+		return filepath.Join(procRoot, "1", "net")
+	}
+	return filepath.Join(procRoot, "net")
+}
+
+type socketEntry struct {
+	localIP   net.IP
+	localPort uint16
+	state     uint8
+	uid       uint32
+	inode     uint64
+}
+
+// parseNetTable parses /proc/net/{tcp,tcp6,udp,udp6}.
+func parseNetTable(r io.Reader) ([]socketEntry, error) {
+	var entries []socketEntry
+
+	scanner := bufio.NewScanner(r)
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("unexpected format: %q", scanner.Text())
+		}
+
+		ip, port, err := parseSocketAddress(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("local address %q: %w", fields[1], err)
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("state %q: %w", fields[3], err)
+		}
+		uid, err := strconv.ParseUint(fields[7], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("uid %q: %w", fields[7], err)
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("inode %q: %w", fields[9], err)
+		}
+
+		entries = append(entries, socketEntry{
+			localIP:   ip,
+			localPort: port,
+			state:     uint8(state),
+			uid:       uint32(uid),
+			inode:     inode,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseSocketAddress decodes "0100007F:1F90". The address is written as
+// 32-bit words in host (little-endian) byte order.
+func parseSocketAddress(s string) (net.IP, uint16, error) {
+	addr, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("missing ':'")
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	raw, err := hex.DecodeString(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(raw) != net.IPv4len && len(raw) != net.IPv6len {
+		return nil, 0, fmt.Errorf("unexpected address length %d", len(raw))
+	}
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for b := 0; b < 4; b++ {
+			ip[word+b] = raw[word+3-b]
+		}
+	}
+	return ip, uint16(port), nil
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+var pressureResources = []string{"cpu", "memory", "io"}
+
+type pressureTotals struct {
+	some uint64
+	full uint64
+}
+
+// pressureCollector reports the pressure stall information of /proc/pressure.
+// The stall percentages are computed from the cumulative stall time between
+// the previous and the current call, so that the window averages cover the
+// window rather than the fixed 10, 60 and 300 seconds of the kernel. The first
+// call only remembers the counters.
+type pressureCollector struct {
+	dir      string
+	prev     map[string]pressureTotals
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newPressureCollector(procRoot string) *pressureCollector {
+	return &pressureCollector{dir: filepath.Join(procRoot, "pressure"), now: time.Now}
+}
+
+func (c *pressureCollector) Name() string {
+	return string(entity.CategoryPressure)
+}
+
+func (c *pressureCollector) ReportsRates() {}
+
+// Available reports an error when the kernel has no PSI: /proc/pressure is
+// missing before 4.20 or with psi=0, and some kernels keep the files but fail
+// to read them.
+func (c *pressureCollector) Available() error {
+	_, err := os.ReadFile(filepath.Join(c.dir, "cpu"))
+	return err
+}
+
+func (c *pressureCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	now := c.now()
+	stats := make([]entity.PressureStats, 0, len(pressureResources))
+	for _, resource := range pressureResources {
+		s, err := c.read(resource)
+		if err != nil {
+			return err
+		}
+		stats = append(stats, s)
+	}
+
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = make(map[string]pressureTotals, len(stats)), now
+	for idx := range stats {
+		s := &stats[idx]
+		c.prev[s.Resource] = pressureTotals{some: s.Some.TotalUsec, full: s.Full.TotalUsec}
+	}
+	if prev == nil || elapsed <= 0 {
+		return nil
+	}
+
+	// The stall times are 64-bit counters on every kernel.
+	deltas := counterDeltas{elapsed: elapsed}
+	for idx := range stats {
+		s := &stats[idx]
+		before := prev[s.Resource]
+		s.Some.Percent = stallPercent(deltas.add(before.some, s.Some.TotalUsec, 0), elapsed)
+		s.Full.Percent = stallPercent(deltas.add(before.full, s.Full.TotalUsec, 0), elapsed)
+	}
+	if deltas.reset {
+		return nil
+	}
+	m.Pressure = stats
+	return nil
+}
+
+func (c *pressureCollector) read(resource string) (entity.PressureStats, error) {
+	path := filepath.Join(c.dir, resource)
+	f, err := os.Open(path)
+	if err != nil {
+		return entity.PressureStats{}, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	s, err := parsePressure(f)
+	if err != nil {
+		return entity.PressureStats{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	s.Resource = resource
+	return s, nil
+}
+
+// stallPercent returns the share of the elapsed time, in percent, covered by
+// an increase of a stall time counter in microseconds.
+func stallPercent(delta uint64, elapsed float64) float64 {
+	return min(float64(delta)/(elapsed*1e6)*100, 100)
+}
+
+// parsePressure parses a file of /proc/pressure:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// The "full" line of cpu only appears with 5.13, it is left zero before.
+func parsePressure(r io.Reader) (entity.PressureStats, error) {
+	var s entity.PressureStats
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var line *entity.PressureLine
+		switch fields[0] {
+		case "some":
+			line = &s.Some
+		case "full":
+			line = &s.Full
+		default:
+			return s, fmt.Errorf("unexpected line: %q", scanner.Text())
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return s, fmt.Errorf("unexpected field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				line.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				line.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				line.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				line.TotalUsec, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return s, fmt.Errorf("%s %s: %w", fields[0], key, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
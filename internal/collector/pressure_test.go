@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+func TestParsePressure(t *testing.T) {
+	s, err := parsePressure(strings.NewReader(
+		"some avg10=1.12 avg60=0.25 avg300=0.08 total=18284567\nfull avg10=0.50 avg60=0.10 avg300=0.02 total=912\n"))
+	require.NoError(t, err)
+	require.Equal(t, entity.PressureStats{
+		Some: entity.PressureLine{Avg10: 1.12, Avg60: 0.25, Avg300: 0.08, TotalUsec: 18284567},
+		Full: entity.PressureLine{Avg10: 0.5, Avg60: 0.1, Avg300: 0.02, TotalUsec: 912},
+	}, s)
+
+	// The cpu file of kernels older than 5.13 has no "full" line.
+	s, err = parsePressure(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=42\n"))
+	require.NoError(t, err)
+	require.Equal(t, entity.PressureStats{Some: entity.PressureLine{TotalUsec: 42}}, s)
+
+	_, err = parsePressure(strings.NewReader("some avg10=0.00 total=x\n"))
+	require.Error(t, err)
+	_, err = parsePressure(strings.NewReader("half avg10=0.00\n"))
+	require.Error(t, err)
+}
+
+func TestPressureCollector(t *testing.T) {
+	root := t.TempDir()
+	write := func(cpu, ioSome, ioFull int) {
+		line := "some avg10=0.00 avg60=0.00 avg300=0.00 total=%d\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=%d\n"
+		writeFile(t, filepath.Join(root, "pressure", "cpu"), fmt.Sprintf(line, cpu, 0))
+		writeFile(t, filepath.Join(root, "pressure", "memory"), fmt.Sprintf(line, 0, 0))
+		writeFile(t, filepath.Join(root, "pressure", "io"), fmt.Sprintf(line, ioSome, ioFull))
+	}
+	now := time.Unix(1700000000, 0)
+	c := newPressureCollector(root)
+	c.now = func() time.Time { return now }
+
+	write(1000, 5000, 2000)
+	require.NoError(t, c.Available())
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Nil(t, m.Pressure)
+
+	// 2 seconds later: 0.5s of cpu stall is 25%, io stalls beyond the elapsed
+	// time are capped at 100%.
+	write(501000, 3005000, 202000)
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.PressureStats{
+		{Resource: "cpu", Some: entity.PressureLine{Percent: 25, TotalUsec: 501000}},
+		{Resource: "memory"},
+		{
+			Resource: "io",
+			Some:     entity.PressureLine{Percent: 100, TotalUsec: 3005000},
+			Full:     entity.PressureLine{Percent: 10, TotalUsec: 202000},
+		},
+	}, m.Pressure)
+}
+
+func TestPressureCollector_Unavailable(t *testing.T) {
+	require.Error(t, newPressureCollector(t.TempDir()).Available())
+}
@@ -0,0 +1,306 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/capture"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const (
+	protocolTCP   = "TCP"
+	protocolUDP   = "UDP"
+	protocolICMP  = "ICMP"
+	protocolARP   = "ARP"
+	protocolIPv6  = "IPv6"
+	protocolOther = "other"
+)
+
+var capturedProtocols = []string{protocolTCP, protocolUDP, protocolICMP, protocolARP, protocolIPv6, protocolOther}
+
+// trafficCounters is a reading of the kernel counters used when packets
+// cannot be captured.
+type trafficCounters struct {
+	snmp map[string]uint64
+	dev  map[string]netDevStats
+}
+
+// protocolsCollector reports bytes per protocol since the previous call. It
+// counts captured packets when capture is configured and works, otherwise it
+// estimates the breakdown from /proc/net counters. The first call after the
+// source changes only sets the baseline.
+type protocolsCollector struct {
+	capture *sharedCapture
+	netDir  string
+
+	mu       sync.Mutex
+	captured map[string]uint64
+	source   entity.TrafficSource
+	prev     *trafficCounters
+	prevTime time.Time
+	now      func() time.Time
+}
+
+func newProtocolsCollector(procRoot string, shared *sharedCapture) *protocolsCollector {
+	c := &protocolsCollector{
+		capture:  shared,
+		netDir:   netDir(procRoot),
+		captured: make(map[string]uint64),
+		now:      time.Now,
+	}
+	if shared != nil {
+		shared.AddHandler(c.observe)
+	}
+	return c
+}
+
+func (c *protocolsCollector) Name() string {
+	return string(entity.CategoryProtocols)
+}
+
+func (c *protocolsCollector) ReportsRates() {}
+
+func (c *protocolsCollector) Available() error {
+	if c.capture != nil {
+		return nil
+	}
+	return fileAvailable(filepath.Join(c.netDir, "snmp"))
+}
+
+func (c *protocolsCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	if c.capture != nil {
+		if c.source != entity.TrafficSourceCapture {
+			// Packets left from an earlier capture belong to no interval.
+			c.drainCaptured()
+		}
+		if c.capture.Start(c.Name()) == nil {
+			c.collectCaptured(m)
+			return nil
+		}
+	}
+	// The capture error is reported by the talkers collector.
+	return c.collectProcfs(m)
+}
+
+// Stop releases the capture, if any.
+func (c *protocolsCollector) Stop() {
+	if c.capture != nil {
+		c.capture.Stop(c.Name())
+	}
+}
+
+func (c *protocolsCollector) observe(p *capture.Packet) {
+	protocol := classifyPacket(p)
+	c.mu.Lock()
+	c.captured[protocol] += uint64(p.Length)
+	c.mu.Unlock()
+}
+
+// classifyPacket attributes a packet to its transport protocol first, so
+// IPv6 only counts IPv6 packets carrying something else.
+func classifyPacket(p *capture.Packet) string {
+	switch p.Network {
+	case capture.NetworkARP:
+		return protocolARP
+	case capture.NetworkIPv4, capture.NetworkIPv6:
+		switch p.Transport {
+		case capture.TransportTCP:
+			return protocolTCP
+		case capture.TransportUDP:
+			return protocolUDP
+		case capture.TransportICMP, capture.TransportICMPv6:
+			return protocolICMP
+		}
+		if p.Network == capture.NetworkIPv6 {
+			return protocolIPv6
+		}
+	}
+	return protocolOther
+}
+
+func (c *protocolsCollector) drainCaptured() map[string]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	captured := c.captured
+	c.captured = make(map[string]uint64)
+	return captured
+}
+
+func (c *protocolsCollector) collectCaptured(m *entity.SystemMetrics) {
+	if c.source != entity.TrafficSourceCapture {
+		c.source, c.prev = entity.TrafficSourceCapture, nil
+		return
+	}
+	captured := c.drainCaptured()
+
+	protocols := make([]entity.ProtocolTraffic, 0, len(capturedProtocols))
+	for _, name := range capturedProtocols {
+		protocols = append(protocols, entity.ProtocolTraffic{Protocol: name, Bytes: captured[name]})
+	}
+	m.Protocols = withPercent(protocols)
+	m.ProtocolSource = entity.TrafficSourceCapture
+}
+
+func (c *protocolsCollector) collectProcfs(m *entity.SystemMetrics) error {
+	now := c.now()
+	cur, err := c.readCounters()
+	if err != nil {
+		return err
+	}
+
+	prev, elapsed := c.prev, now.Sub(c.prevTime).Seconds()
+	c.prev, c.prevTime = cur, now
+	if c.source != entity.TrafficSourceProcfs || prev == nil {
+		c.source = entity.TrafficSourceProcfs
+		return nil
+	}
+
+	protocols, ok := procfsTraffic(prev, cur, elapsed)
+	if !ok {
+		return nil
+	}
+	m.Protocols = withPercent(protocols)
+	m.ProtocolSource = entity.TrafficSourceProcfs
+	return nil
+}
+
+func (c *protocolsCollector) readCounters() (*trafficCounters, error) {
+	counters := &trafficCounters{
+		snmp: make(map[string]uint64),
+		dev:  make(map[string]netDevStats),
+	}
+
+	files := []struct {
+		name     string
+		parse    func(io.Reader, map[string]uint64) error
+		optional bool
+	}{
+		{"snmp", parseSnmpTable, false},
+		{"netstat", parseSnmpTable, false},
+		// Missing when IPv6 is disabled.
+		{"snmp6", parseNameValues, true},
+	}
+	for _, file := range files {
+		path := filepath.Join(c.netDir, file.name)
+		f, err := os.Open(path)
+		if err != nil {
+			if file.optional && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		err = file.parse(f, counters.snmp)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	path := filepath.Join(c.netDir, "dev")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	devices, err := parseNetDev(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, d := range devices {
+		counters.dev[d.name] = d.netDevStats
+	}
+	return counters, nil
+}
+
+// procfsTraffic estimates the breakdown between two readings. The kernel only
+// counts IP bytes in total, so they are split between TCP, UDP and ICMP by
+// their share of IP packets. Whatever remains, and the link traffic that is
+// not IP at all, is reported as other. ARP and plain IPv6 cannot be told apart
+// from the counters. Kernels older than 2.6.24 have no IP byte counters, then
+// all link traffic is taken for IP. It reports false when a protocol counter
+// was reset; interfaces whose counters were reset are left out. The readings
+// are elapsed seconds apart.
+func procfsTraffic(prev, cur *trafficCounters, elapsed float64) ([]entity.ProtocolTraffic, bool) {
+	deltas := counterDeltas{elapsed: elapsed}
+	delta := func(maxRate float64, keys ...string) uint64 {
+		var sum uint64
+		for _, key := range keys {
+			sum += deltas.add(prev.snmp[key], cur.snmp[key], maxRate)
+		}
+		return sum
+	}
+
+	var linkBytes uint64
+	for name, stats := range cur.dev {
+		before, ok := prev.dev[name]
+		if !ok {
+			continue
+		}
+		link := counterDeltas{elapsed: elapsed}
+		traffic := link.add(before.rxBytes, stats.rxBytes, maxLinkBytesPerSec) +
+			link.add(before.txBytes, stats.txBytes, maxLinkBytesPerSec)
+		if !link.reset {
+			linkBytes += traffic
+		}
+	}
+
+	// The octet counters are 64 bits wide on every kernel.
+	ipBytes := delta(0, "IpExtInOctets", "IpExtOutOctets", "Ip6InOctets", "Ip6OutOctets")
+	if _, ok := cur.snmp["IpExtInOctets"]; !ok {
+		ipBytes = linkBytes
+	}
+	ipPackets := delta(maxLinkPacketsPerSec, "IpInReceives", "IpOutRequests", "Ip6InReceives", "Ip6OutRequests")
+	packets := []uint64{
+		delta(maxLinkPacketsPerSec, "TcpInSegs", "TcpOutSegs"),
+		delta(maxLinkPacketsPerSec, "UdpInDatagrams", "UdpOutDatagrams", "Udp6InDatagrams", "Udp6OutDatagrams"),
+		delta(maxLinkPacketsPerSec, "IcmpInMsgs", "IcmpOutMsgs", "Icmp6InMsgs", "Icmp6OutMsgs"),
+	}
+
+	var transportPackets uint64
+	for _, n := range packets {
+		transportPackets += n
+	}
+	// The counters are not updated atomically, the transport ones may run
+	// ahead of the IP ones.
+	if transportPackets > ipPackets {
+		ipPackets = transportPackets
+	}
+
+	protocols := make([]entity.ProtocolTraffic, 0, len(packets)+1)
+	var attributed uint64
+	for idx, name := range []string{protocolTCP, protocolUDP, protocolICMP} {
+		var bytes uint64
+		if ipPackets > 0 {
+			bytes = uint64(float64(ipBytes) * float64(packets[idx]) / float64(ipPackets))
+		}
+		attributed += bytes
+		protocols = append(protocols, entity.ProtocolTraffic{Protocol: name, Bytes: bytes})
+	}
+
+	other := ipBytes - attributed
+	if linkBytes > ipBytes {
+		other += linkBytes - ipBytes
+	}
+	return append(protocols, entity.ProtocolTraffic{Protocol: protocolOther, Bytes: other}), !deltas.reset
+}
+
+func withPercent(protocols []entity.ProtocolTraffic) []entity.ProtocolTraffic {
+	var total uint64
+	for _, p := range protocols {
+		total += p.Bytes
+	}
+	if total == 0 {
+		return protocols
+	}
+	for idx := range protocols {
+		protocols[idx].Percent = float64(protocols[idx].Bytes) / float64(total) * 100
+	}
+	return protocols
+}
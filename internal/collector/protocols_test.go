@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/capture"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const netDevSample = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 29099395    3847    0    0    0     0          0         0 29099395    3847    0    0    0     0       0          0
+  eth0:  470408     238    1    2    0     0          0         0    37818     263    3    4    0     0       0          0
+`
+
+func TestParseNetDev(t *testing.T) {
+	devices, err := parseNetDev(strings.NewReader(netDevSample))
+	require.NoError(t, err)
+	require.Equal(t, []netDevice{
+		{name: "lo", netDevStats: netDevStats{
+			rxBytes: 29099395, rxPackets: 3847, txBytes: 29099395, txPackets: 3847,
+		}},
+		{name: "eth0", netDevStats: netDevStats{
+			rxBytes: 470408, rxPackets: 238, rxErrs: 1, rxDrop: 2,
+			txBytes: 37818, txPackets: 263, txErrs: 3, txDrop: 4,
+		}},
+	}, devices)
+
+	_, err = parseNetDev(strings.NewReader("eth0: 1 2 3\n"))
+	require.Error(t, err)
+}
+
+func TestParseSnmpTable(t *testing.T) {
+	data := `Ip: Forwarding DefaultTTL InReceives OutRequests
+Ip: 2 64 4057 4080
+Tcp: RtoAlgorithm MaxConn InSegs OutSegs
+Tcp: 1 -1 4023 4055
+`
+	counters := make(map[string]uint64)
+	require.NoError(t, parseSnmpTable(strings.NewReader(data), counters))
+	require.Equal(t, uint64(4057), counters["IpInReceives"])
+	require.Equal(t, uint64(4055), counters["TcpOutSegs"])
+	require.NotContains(t, counters, "TcpMaxConn")
+
+	err := parseSnmpTable(strings.NewReader("Ip: InReceives OutRequests\nIp: 1\n"), counters)
+	require.Error(t, err)
+	err = parseSnmpTable(strings.NewReader("Ip: InReceives\n"), counters)
+	require.Error(t, err)
+}
+
+func TestParseNameValues(t *testing.T) {
+	data := "Ip6InReceives                   \t3\nIp6InOctets                     \t224\n"
+	counters := make(map[string]uint64)
+	require.NoError(t, parseNameValues(strings.NewReader(data), counters))
+	require.Equal(t, map[string]uint64{"Ip6InReceives": 3, "Ip6InOctets": 224}, counters)
+
+	data = "MemTotal:        8141132 kB\nHugePages_Total:       0\n"
+	counters = make(map[string]uint64)
+	require.NoError(t, parseNameValues(strings.NewReader(data), counters))
+	require.Equal(t, map[string]uint64{"MemTotal": 8141132, "HugePages_Total": 0}, counters)
+
+	require.Error(t, parseNameValues(strings.NewReader("MemTotal: 8141132 MB\n"), counters))
+	require.Error(t, parseNameValues(strings.NewReader("pswpin -1\n"), counters))
+}
+
+func TestClassifyPacket(t *testing.T) {
+	tests := []struct {
+		network   capture.Network
+		transport capture.Transport
+		want      string
+	}{
+		{capture.NetworkIPv4, capture.TransportTCP, protocolTCP},
+		{capture.NetworkIPv6, capture.TransportUDP, protocolUDP},
+		{capture.NetworkIPv4, capture.TransportICMP, protocolICMP},
+		{capture.NetworkIPv6, capture.TransportICMPv6, protocolICMP},
+		{capture.NetworkARP, capture.TransportNone, protocolARP},
+		{capture.NetworkIPv6, capture.TransportOther, protocolIPv6},
+		{capture.NetworkIPv4, capture.TransportOther, protocolOther},
+		{capture.NetworkOther, capture.TransportNone, protocolOther},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.network, tt.transport), func(t *testing.T) {
+			p := &capture.Packet{Network: tt.network, Transport: tt.transport}
+			require.Equal(t, tt.want, classifyPacket(p))
+		})
+	}
+}
+
+func TestProcfsTraffic(t *testing.T) {
+	prev := &trafficCounters{
+		snmp: map[string]uint64{},
+		dev:  map[string]netDevStats{"eth0": {}},
+	}
+	cur := &trafficCounters{
+		snmp: map[string]uint64{
+			"IpExtInOctets": 6000, "IpExtOutOctets": 4000,
+			"IpInReceives": 60, "IpOutRequests": 40,
+			"TcpInSegs": 50, "TcpOutSegs": 25,
+			"UdpInDatagrams": 10, "Udp6OutDatagrams": 10,
+			"IcmpInMsgs": 5,
+		},
+		dev: map[string]netDevStats{
+			"eth0": {rxBytes: 9000, txBytes: 3000},
+			// Appeared after the previous reading, so it has no delta yet.
+			"eth1": {rxBytes: 1 << 20},
+		},
+	}
+
+	traffic, ok := procfsTraffic(prev, cur, 1)
+	require.True(t, ok)
+	require.Equal(t, []entity.ProtocolTraffic{
+		{Protocol: protocolTCP, Bytes: 7500},
+		{Protocol: protocolUDP, Bytes: 2000},
+		{Protocol: protocolICMP, Bytes: 500},
+		{Protocol: protocolOther, Bytes: 2000},
+	}, traffic)
+
+	// Without IP byte counters the link traffic is split instead.
+	delete(cur.snmp, "IpExtInOctets")
+	delete(cur.snmp, "IpExtOutOctets")
+	traffic, ok = procfsTraffic(prev, cur, 1)
+	require.True(t, ok)
+	require.Equal(t, []entity.ProtocolTraffic{
+		{Protocol: protocolTCP, Bytes: 9000},
+		{Protocol: protocolUDP, Bytes: 2400},
+		{Protocol: protocolICMP, Bytes: 600},
+		{Protocol: protocolOther, Bytes: 0},
+	}, traffic)
+
+	// A reset protocol counter drops the reading.
+	prev.snmp["TcpInSegs"] = 100
+	_, ok = procfsTraffic(prev, cur, 1)
+	require.False(t, ok)
+}
+
+func TestProtocolsCollectorProcfs(t *testing.T) {
+	root := t.TempDir()
+	write := func(inOctets, inSegs, rxBytes int) {
+		writeFile(t, filepath.Join(root, "1", "net", "snmp"), fmt.Sprintf(
+			"Ip: InReceives OutRequests\nIp: %d 0\nTcp: InSegs OutSegs\nTcp: %d 0\n", inSegs, inSegs))
+		writeFile(t, filepath.Join(root, "1", "net", "netstat"), fmt.Sprintf(
+			"IpExt: InOctets OutOctets\nIpExt: %d 0\n", inOctets))
+		writeFile(t, filepath.Join(root, "1", "net", "dev"), fmt.Sprintf(
+			"header\nheader\n  eth0: %d 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n", rxBytes))
+	}
+
+	c := newProtocolsCollector(root, nil)
+
+	write(1000, 10, 1000)
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Nil(t, m.Protocols)
+
+	write(3000, 20, 5000)
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, entity.TrafficSourceProcfs, m.ProtocolSource)
+	require.Equal(t, []entity.ProtocolTraffic{
+		{Protocol: protocolTCP, Bytes: 2000, Percent: 50},
+		{Protocol: protocolUDP},
+		{Protocol: protocolICMP},
+		{Protocol: protocolOther, Bytes: 2000, Percent: 50},
+	}, m.Protocols)
+}
@@ -0,0 +1,199 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+	i "github.com/dimryb/system-monitor/internal/interface"
+)
+
+var errCaptureDisabled = errors.New("packet capture is disabled")
+
+// ErrUnavailable matches collection errors that persist until the host
+// changes, such as missing privileges for packet capture.
+var ErrUnavailable = errors.New("unavailable on this host")
+
+// unavailableError keeps the message of the cause and matches ErrUnavailable.
+type unavailableError struct {
+	err error
+}
+
+func unavailable(err error) error {
+	return unavailableError{err: err}
+}
+
+func (e unavailableError) Error() string        { return e.err.Error() }
+func (e unavailableError) Unwrap() error        { return e.err }
+func (e unavailableError) Is(target error) bool { return target == ErrUnavailable }
+
+// environment holds what several collectors share.
+type environment struct {
+	cfg     config.Collectors
+	capture *sharedCapture
+}
+
+type factory func(env *environment) (i.Collector, error)
+
+type registration struct {
+	category func(cfg config.Collectors) config.Category
+	build    factory
+}
+
+// registry lists the collectors in the order they fill a snapshot.
+var registry = []registration{
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.LoadAvg },
+		build: func(env *environment) (i.Collector, error) {
+			return newLoadAvgCollector(env.cfg.Host.ProcPath()), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.CPU.Category },
+		build: func(env *environment) (i.Collector, error) {
+			return newCPUCollector(env.cfg.Host.ProcPath(), env.cfg.CPU.PerCore), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Memory },
+		build: func(env *environment) (i.Collector, error) {
+			return newMemoryCollector(env.cfg.Host.ProcPath()), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Pressure },
+		build: func(env *environment) (i.Collector, error) {
+			return newPressureCollector(env.cfg.Host.ProcPath()), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Cgroups.Category },
+		build: func(env *environment) (i.Collector, error) {
+			return newCgroupsCollector(env.cfg.Host.SysPath(), env.cfg.Cgroups.Top), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Disk.Category },
+		build: func(env *environment) (i.Collector, error) {
+			filter, err := newNameFilter(env.cfg.Disk.Include, env.cfg.Disk.ExcludePatterns())
+			if err != nil {
+				return nil, fmt.Errorf("disk collector: %w", err)
+			}
+			return newDiskCollector(env.cfg.Host.ProcPath(), filter), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Fs.Category },
+		build: func(env *environment) (i.Collector, error) {
+			fs, host := env.cfg.Fs, env.cfg.Host
+			return newFsCollector(host.ProcPath(), host.RootPath(), fs.IncludeTypes, fs.ExcludedTypes(), fs.StatfsTimeout), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Interfaces.Category },
+		build: func(env *environment) (i.Collector, error) {
+			interfaces, host := env.cfg.Interfaces, env.cfg.Host
+			filter, err := newNameFilter(interfaces.Include, interfaces.ExcludePatterns())
+			if err != nil {
+				return nil, fmt.Errorf("interfaces collector: %w", err)
+			}
+			return newInterfacesCollector(host.ProcPath(), host.SysPath(), filter), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Sockets },
+		build: func(env *environment) (i.Collector, error) {
+			return newSocketsCollector(env.cfg.Host.ProcPath(), env.cfg.Host.RootPath()), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.TCPStates },
+		build: func(env *environment) (i.Collector, error) {
+			return newTCPStatesCollector(env.cfg.Host.ProcPath()), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Protocols },
+		build: func(env *environment) (i.Collector, error) {
+			return newProtocolsCollector(env.cfg.Host.ProcPath(), env.capture), nil
+		},
+	},
+	{
+		category: func(cfg config.Collectors) config.Category { return cfg.Talkers.Category },
+		build: func(env *environment) (i.Collector, error) {
+			return newTalkersCollector(env.capture, env.cfg.Talkers.Top, env.cfg.Talkers.MaxFlows), nil
+		},
+	},
+}
+
+// Scheduled is a collector of an enabled category with its collection period.
+type Scheduled struct {
+	i.Collector
+	Period time.Duration
+}
+
+// categorized is implemented by the collectors sharing a category, whose
+// names differ from it.
+type categorized interface {
+	Category() entity.Category
+}
+
+// Category returns the category the collector fills, its name by default.
+func (s Scheduled) Category() entity.Category {
+	if c, ok := s.Collector.(categorized); ok {
+		return c.Category()
+	}
+	return entity.Category(s.Name())
+}
+
+// New builds the collectors of the enabled categories, followed by one per
+// enabled custom metric. Collectors that cannot work on this host are returned
+// too, their Available method tells why.
+func New(cfg config.Collectors) ([]Scheduled, error) {
+	env := &environment{cfg: cfg}
+	if cfg.Capture.Enabled {
+		shared, err := newSharedCapture(cfg.Capture)
+		if err != nil {
+			return nil, fmt.Errorf("capture: %w", err)
+		}
+		env.capture = shared
+	}
+
+	collectors := make([]Scheduled, 0, len(registry))
+	for _, r := range registry {
+		category := r.category(cfg)
+		if !category.IsEnabled() {
+			continue
+		}
+		c, err := r.build(env)
+		if err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, Scheduled{Collector: c, Period: category.Interval()})
+	}
+
+	names := make(map[string]bool, len(cfg.Custom))
+	for _, metric := range cfg.Custom {
+		if names[metric.Name] {
+			return nil, fmt.Errorf("custom metric %s is defined twice", metric.Name)
+		}
+		names[metric.Name] = true
+		if !metric.IsEnabled() {
+			continue
+		}
+		c, err := newCustomCollector(metric)
+		if err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, Scheduled{Collector: c, Period: metric.Interval()})
+	}
+	return collectors, nil
+}
+
+func fileAvailable(path string) error {
+	_, err := os.Stat(path)
+	return err
+}
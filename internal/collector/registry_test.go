@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+	i "github.com/dimryb/system-monitor/internal/interface"
+)
+
+func TestNew(t *testing.T) {
+	collectors, err := New(config.Collectors{})
+	require.NoError(t, err)
+
+	var names, rates []string
+	for _, c := range collectors {
+		names = append(names, c.Name())
+		if _, ok := c.Collector.(i.RateCollector); ok {
+			rates = append(rates, c.Name())
+		}
+	}
+	require.Equal(t, []string{
+		"loadavg", "cpu", "memory", "pressure", "cgroups", "disk", "fs", "interfaces", "sockets", "tcp_states", "protocols", "talkers",
+	}, names)
+	require.Equal(t, []string{
+		"cpu", "memory", "pressure", "cgroups", "disk", "interfaces", "protocols", "talkers",
+	}, rates)
+	require.ErrorIs(t, collectors[len(collectors)-1].Available(), errCaptureDisabled)
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(config.Collectors{Disk: config.DiskCollector{Include: []string{"("}}})
+	require.ErrorContains(t, err, "disk collector")
+
+	_, err = New(config.Collectors{Capture: config.CaptureConfig{Enabled: true, File: "x.pcap", Replay: "slow"}})
+	require.ErrorContains(t, err, "replay mode")
+}
+
+func TestNew_Custom(t *testing.T) {
+	disabled := false
+	collectors, err := New(config.Collectors{Custom: []config.CustomMetric{
+		{Name: "queue", Command: "postqueue", Category: config.Category{Period: time.Minute}},
+		{Name: "backups", Command: "backup-status", Category: config.Category{Enabled: &disabled}},
+	}})
+	require.NoError(t, err)
+
+	last := collectors[len(collectors)-1]
+	require.Equal(t, "custom:queue", last.Name())
+	require.Equal(t, entity.CategoryCustom, last.Category())
+	require.Equal(t, time.Minute, last.Period)
+	require.Equal(t, entity.CategoryTalkers, collectors[len(collectors)-2].Category())
+
+	for _, tt := range []struct {
+		metrics []config.CustomMetric
+		err     string
+	}{
+		{[]config.CustomMetric{{Command: "true"}}, "without a name"},
+		{[]config.CustomMetric{{Name: "queue"}}, "no command"},
+		{[]config.CustomMetric{{Name: "queue", Command: "true", Output: "xml"}}, "unknown output"},
+		{[]config.CustomMetric{{Name: "queue", Command: "true", Timeout: time.Minute}}, "exceeds the period"},
+		{[]config.CustomMetric{{Name: "queue", Command: "true", Args: []string{"-v"}, Shell: true}}, "shell"},
+		{[]config.CustomMetric{{Name: "queue", Command: "true"}, {Name: "queue", Command: "true"}}, "defined twice"},
+	} {
+		_, err := New(config.Collectors{Custom: tt.metrics})
+		require.ErrorContains(t, err, tt.err)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	c := newLoadAvgCollector("testdata/missing")
+	require.Error(t, c.Available())
+}
+
+func TestNew_Categories(t *testing.T) {
+	disabled := false
+	collectors, err := New(config.Collectors{
+		CPU:       config.CPUCollector{Category: config.Category{Enabled: &disabled}},
+		Disk:      config.DiskCollector{Category: config.Category{Period: 5 * time.Second}},
+		Protocols: config.Category{Enabled: &disabled},
+	})
+	require.NoError(t, err)
+
+	periods := make(map[string]time.Duration)
+	for _, c := range collectors {
+		periods[c.Name()] = c.Period
+	}
+	require.NotContains(t, periods, "cpu")
+	require.NotContains(t, periods, "protocols")
+	require.Equal(t, 5*time.Second, periods["disk"])
+	require.Equal(t, config.DefaultPeriod, periods["loadavg"])
+}
+
+func TestNew_HostPaths(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "proc", "loadavg"), "0.50 0.25 0.10 2/312 4242\n")
+	writeFile(t, filepath.Join(root, "proc", "1", "mountinfo"), "28 1 253:1 / / rw - ext4 /dev/vda1 rw\n")
+
+	collectors, err := New(config.Collectors{
+		Host: config.Host{Proc: filepath.Join(root, "proc"), Root: root},
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]Scheduled)
+	for _, c := range collectors {
+		byName[c.Name()] = c
+	}
+	require.NoError(t, byName["fs"].Available())
+	require.Error(t, byName["cpu"].Available())
+
+	m := &entity.SystemMetrics{}
+	require.NoError(t, byName["loadavg"].Collect(context.Background(), m))
+	require.Equal(t, &entity.LoadAverage{
+		OneMin: 0.5, FiveMins: 0.25, FifteenMins: 0.1, RunningTasks: 2, TotalTasks: 312,
+	}, m.LoadAverage)
+}
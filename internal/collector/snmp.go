@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parseSnmpTable parses /proc/net/snmp and /proc/net/netstat, where every
+// section is a line of names followed by a line of values. The counters are
+// keyed like nstat does it, e.g. "TcpInSegs". Values that are not counters,
+// like the -1 of TcpMaxConn, are skipped.
+func parseSnmpTable(r io.Reader, counters map[string]uint64) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		names := strings.Fields(scanner.Text())
+		if !scanner.Scan() {
+			return fmt.Errorf("section %q: values line is missing", strings.Join(names, " "))
+		}
+		values := strings.Fields(scanner.Text())
+		if len(names) == 0 || len(values) != len(names) || names[0] != values[0] {
+			return fmt.Errorf("unexpected format: %q", scanner.Text())
+		}
+
+		prefix := strings.TrimSuffix(names[0], ":")
+		for idx := 1; idx < len(names); idx++ {
+			v, err := strconv.ParseUint(values[idx], 10, 64)
+			if err != nil {
+				continue
+			}
+			counters[prefix+names[idx]] = v
+		}
+	}
+	return scanner.Err()
+}
+
+// parseNameValues parses files with one "name value" pair per line, such as
+// /proc/net/snmp6 and /proc/vmstat. It also reads /proc/meminfo, where names
+// end with a colon and values may be followed by their unit, always kB.
+func parseNameValues(r io.Reader, counters map[string]uint64) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 && (len(fields) != 3 || fields[2] != "kB") {
+			return fmt.Errorf("unexpected format: %q", scanner.Text())
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fields[0], err)
+		}
+		counters[strings.TrimSuffix(fields[0], ":")] = v
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,241 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+const unknownOwner = "unknown"
+
+type socketOwner struct {
+	pid     int32
+	command string
+}
+
+type socketsCollector struct {
+	procRoot   string
+	netDir     string
+	passwdPath string
+
+	owners map[uint64]socketOwner
+	users  map[uint32]string
+	passwd time.Time
+}
+
+func newSocketsCollector(procRoot, hostRoot string) *socketsCollector {
+	return &socketsCollector{
+		procRoot:   procRoot,
+		netDir:     netDir(procRoot),
+		passwdPath: filepath.Join(hostRoot, "etc", "passwd"),
+		owners:     make(map[uint64]socketOwner),
+	}
+}
+
+func (c *socketsCollector) Name() string {
+	return string(entity.CategorySockets)
+}
+
+func (c *socketsCollector) Available() error {
+	return fileAvailable(filepath.Join(c.netDir, "tcp"))
+}
+
+// Collect reports listening TCP sockets and bound UDP sockets. Processes are
+// found by scanning /proc/[pid]/fd, which requires root for foreign processes;
+// sockets that cannot be attributed are reported with an "unknown" command.
+func (c *socketsCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	type listener struct {
+		protocol string
+		entry    socketEntry
+	}
+
+	var listeners []listener
+	for _, protocol := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		path := filepath.Join(c.netDir, protocol)
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // e.g. IPv6 is disabled
+			}
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+		entries, err := parseNetTable(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		listenState := uint8(tcpListen)
+		if strings.HasPrefix(protocol, "udp") {
+			listenState = udpClose
+		}
+		for _, e := range entries {
+			if e.state == listenState && e.inode != 0 {
+				listeners = append(listeners, listener{protocol: protocol, entry: e})
+			}
+		}
+	}
+
+	inodes := make([]uint64, 0, len(listeners))
+	for _, l := range listeners {
+		inodes = append(inodes, l.entry.inode)
+	}
+	c.resolveOwners(inodes)
+	c.loadUsers()
+
+	connections := make([]entity.NetworkConnection, 0, len(listeners))
+	for _, l := range listeners {
+		owner, ok := c.owners[l.entry.inode]
+		if !ok {
+			owner.command = unknownOwner
+		}
+		connections = append(connections, entity.NetworkConnection{
+			Command:  owner.command,
+			PID:      owner.pid,
+			User:     c.userName(l.entry.uid),
+			Protocol: l.protocol,
+			Port:     int32(l.entry.localPort),
+		})
+	}
+
+	m.Connections = connections
+	return nil
+}
+
+// resolveOwners maps socket inodes to processes. /proc is only rescanned while
+// a socket has no known owner: a new socket, or one whose process was not
+// found before, e.g. as it was scanned before the socket was passed to it.
+// Only the owners found are remembered.
+func (c *socketsCollector) resolveOwners(inodes []uint64) {
+	current := make(map[uint64]struct{}, len(inodes))
+	scan := false
+	for _, inode := range inodes {
+		current[inode] = struct{}{}
+		if _, ok := c.owners[inode]; !ok {
+			scan = true
+		}
+	}
+	for inode := range c.owners {
+		if _, ok := current[inode]; !ok {
+			delete(c.owners, inode)
+		}
+	}
+	if !scan {
+		return
+	}
+
+	for inode, owner := range c.scanProcesses(current) {
+		if _, ok := c.owners[inode]; !ok {
+			c.owners[inode] = owner
+		}
+	}
+}
+
+// scanProcesses looks up the wanted socket inodes in /proc/[pid]/fd. Processes
+// are visited in PID order, so a socket shared after fork() is attributed to
+// the parent.
+func (c *socketsCollector) scanProcesses(wanted map[uint64]struct{}) map[uint64]socketOwner {
+	found := make(map[uint64]socketOwner)
+
+	dirs, err := os.ReadDir(c.procRoot)
+	if err != nil {
+		return found
+	}
+	pids := make([]int, 0, len(dirs))
+	for _, d := range dirs {
+		if pid, err := strconv.Atoi(d.Name()); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+
+	for _, pid := range pids {
+		fdDir := filepath.Join(c.procRoot, strconv.Itoa(pid), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue // the process is gone or belongs to another user
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			if _, ok := wanted[inode]; !ok {
+				continue
+			}
+			if _, ok := found[inode]; !ok {
+				found[inode] = socketOwner{pid: int32(pid), command: c.command(pid)}
+			}
+		}
+		if len(found) == len(wanted) {
+			break
+		}
+	}
+	return found
+}
+
+// command returns the name of a process. Kernels before 2.6.33 have no comm
+// file, the name is then taken from stat, where it is put in parentheses.
+func (c *socketsCollector) command(pid int) string {
+	dir := filepath.Join(c.procRoot, strconv.Itoa(pid))
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		return strings.TrimSpace(string(comm))
+	}
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return unknownOwner
+	}
+	start, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return unknownOwner
+	}
+	return string(stat[start+1 : end])
+}
+
+// loadUsers (re)reads the passwd file when it has changed.
+func (c *socketsCollector) loadUsers() {
+	info, err := os.Stat(c.passwdPath)
+	if err != nil || (c.users != nil && info.ModTime().Equal(c.passwd)) {
+		return
+	}
+	f, err := os.Open(c.passwdPath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	users := make(map[uint32]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 {
+			continue
+		}
+		if uid, err := strconv.ParseUint(fields[2], 10, 32); err == nil {
+			if _, ok := users[uint32(uid)]; !ok {
+				users[uint32(uid)] = fields[0]
+			}
+		}
+	}
+	c.users, c.passwd = users, info.ModTime()
+}
+
+func (c *socketsCollector) userName(uid uint32) string {
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(uid), 10)
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+const netTCPHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+func TestParseNetTable(t *testing.T) {
+	data := netTCPHeader +
+		"   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12345 1 0 100 0 0 10 0\n" +
+		"   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 12346 1 0 20 4 30 10 -1\n"
+
+	entries, err := parseNetTable(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, socketEntry{
+		localIP: net.IPv4(127, 0, 0, 1).To4(), localPort: 8080, state: tcpListen, uid: 1000, inode: 12345,
+	}, entries[0])
+	require.Equal(t, uint8(0x01), entries[1].state)
+
+	_, err = parseNetTable(strings.NewReader(netTCPHeader + "   0: 0100007F 00000000:0000 0A\n"))
+	require.Error(t, err)
+}
+
+func TestNetDir(t *testing.T) {
+	require.Equal(t, "/proc/net", netDir("/proc"))
+	require.Equal(t, "/proc/net", netDir("/proc/"))
+	require.Equal(t, "/host/proc/1/net", netDir("/host/proc"))
+}
+
+func TestParseSocketAddress(t *testing.T) {
+	ip, port, err := parseSocketAddress("00000000000000000000000001000000:0016")
+	require.NoError(t, err)
+	require.Equal(t, net.IPv6loopback, ip)
+	require.Equal(t, uint16(22), port)
+
+	_, _, err = parseSocketAddress("0100007F")
+	require.Error(t, err)
+}
+
+func TestSocketsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "proc", "1", "net", "tcp"), netTCPHeader+
+		"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 100 1 0\n"+
+		"   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 200 1 0\n"+
+		"   2: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 300 1 0\n")
+	writeFile(t, filepath.Join(root, "proc", "1", "net", "udp"), netTCPHeader+
+		"   0: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000  4242        0 400 2 0\n")
+	writeFile(t, filepath.Join(root, "proc", "812", "comm"), "sshd\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "proc", "812", "fd"), 0o755))
+	require.NoError(t, os.Symlink("socket:[100]", filepath.Join(root, "proc", "812", "fd", "3")))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(root, "proc", "812", "fd", "0")))
+	// Kernels before 2.6.33 have no comm file.
+	writeFile(t, filepath.Join(root, "proc", "900", "stat"), "900 (dns (v2)) S 1 900 900 0 -1\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "proc", "900", "fd"), 0o755))
+	require.NoError(t, os.Symlink("socket:[400]", filepath.Join(root, "proc", "900", "fd", "5")))
+	writeFile(t, filepath.Join(root, "etc", "passwd"), "root:x:0:0:root:/root:/bin/bash\nalice:x:1000:1000::/home/alice:/bin/sh\n")
+
+	c := newSocketsCollector(filepath.Join(root, "proc"), root)
+
+	m := &entity.SystemMetrics{}
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, []entity.NetworkConnection{
+		{Command: "sshd", PID: 812, User: "root", Protocol: "tcp", Port: 22},
+		{Command: unknownOwner, User: "alice", Protocol: "tcp", Port: 8080},
+		{Command: "dns (v2)", PID: 900, User: "4242", Protocol: "udp", Port: 53},
+	}, m.Connections)
+
+	// The owner of a socket left unknown is looked up again.
+	writeFile(t, filepath.Join(root, "proc", "1200", "comm"), "web\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "proc", "1200", "fd"), 0o755))
+	require.NoError(t, os.Symlink("socket:[200]", filepath.Join(root, "proc", "1200", "fd", "4")))
+	require.NoError(t, c.Collect(context.Background(), m))
+	require.Equal(t, entity.NetworkConnection{
+		Command: "web", PID: 1200, User: "alice", Protocol: "tcp", Port: 8080,
+	}, m.Connections[1])
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
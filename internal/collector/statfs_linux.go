@@ -0,0 +1,25 @@
+//go:build linux
+
+package collector
+
+import "syscall"
+
+func statfs(path string) (fsStat, error) {
+	var s syscall.Statfs_t
+	if err := syscall.Statfs(path, &s); err != nil {
+		return fsStat{}, err
+	}
+
+	blockSize := uint64(s.Frsize) //nolint:unconvert
+	if blockSize == 0 {
+		blockSize = uint64(s.Bsize) //nolint:unconvert
+	}
+	return fsStat{
+		blocks:    s.Blocks,
+		bfree:     s.Bfree,
+		bavail:    s.Bavail,
+		files:     s.Files,
+		ffree:     s.Ffree,
+		blockSize: blockSize,
+	}, nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package collector
+
+import "errors"
+
+func statfs(string) (fsStat, error) {
+	return fsStat{}, errors.New("statfs is not supported on this platform")
+}
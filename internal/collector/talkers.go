@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/capture"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+type talkersCollector struct {
+	capture *sharedCapture
+	top     int
+	flows   *capture.FlowTable
+	now     func() time.Time
+
+	mu        sync.Mutex
+	lastDrain time.Time
+	// lastPacket is the capture timestamp of the last packet drained from a
+	// replayed file.
+	lastPacket time.Time
+}
+
+func newTalkersCollector(shared *sharedCapture, top, maxFlows int) *talkersCollector {
+	c := &talkersCollector{
+		capture: shared,
+		top:     top,
+		flows:   capture.NewFlowTable(maxFlows),
+		now:     time.Now,
+	}
+	if shared != nil {
+		shared.AddHandler(c.flows.Observe)
+	}
+	return c
+}
+
+func (c *talkersCollector) Name() string {
+	return string(entity.CategoryTalkers)
+}
+
+func (c *talkersCollector) ReportsRates() {}
+
+func (c *talkersCollector) Available() error {
+	if c.capture == nil {
+		return errCaptureDisabled
+	}
+	return nil
+}
+
+// Collect reports the top flows by bits per second since the previous call.
+// The capture is started on the first call, which only sets the baseline.
+func (c *talkersCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	if err := c.capture.Start(c.Name()); err != nil {
+		return err
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastDrain.IsZero() {
+		c.lastDrain = now
+		return nil
+	}
+	// The flows are left for the next call when the clock went back.
+	elapsed := now.Sub(c.lastDrain).Seconds()
+	if elapsed <= 0 {
+		return nil
+	}
+	c.lastDrain = now
+
+	flows, first, last := c.flows.Drain()
+	if c.capture.replay && !last.IsZero() {
+		elapsed = c.replayedSeconds(first, last, elapsed)
+	}
+
+	if len(flows) > c.top {
+		flows = flows[:c.top]
+	}
+	talkers := make([]entity.TopTalker, 0, len(flows))
+	for _, f := range flows {
+		talkers = append(talkers, entity.TopTalker{
+			Src:      formatEndpoint(f.Transport, f.Src),
+			Dst:      formatEndpoint(f.Transport, f.Dst),
+			Protocol: string(f.Transport),
+			BPS:      uint64(float64(f.Bytes) * 8 / elapsed),
+		})
+	}
+	m.TopTalkers = talkers
+	return nil
+}
+
+// replayedSeconds returns the time covered by the packets of a replayed file,
+// whose pace a fast replay does not keep: from the last packet of the previous
+// call, or the first one of this call, to the last one. The wall clock is kept
+// when the packets cover no time. The caller holds c.mu.
+func (c *talkersCollector) replayedSeconds(first, last time.Time, wallClock float64) float64 {
+	if !c.lastPacket.IsZero() {
+		first = c.lastPacket
+	}
+	c.lastPacket = last
+	if span := last.Sub(first).Seconds(); span > 0 {
+		return span
+	}
+	return wallClock
+}
+
+// Stop releases the capture. The next call sets the baseline again.
+func (c *talkersCollector) Stop() {
+	c.capture.Stop(c.Name())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastDrain, c.lastPacket = time.Time{}, time.Time{}
+	c.flows.Drain()
+}
+
+func formatEndpoint(transport capture.Transport, addr netip.AddrPort) string {
+	if transport == capture.TransportTCP || transport == capture.TransportUDP {
+		return addr.String()
+	}
+	return addr.Addr().String()
+}
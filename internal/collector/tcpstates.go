@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+// tcpStates lists the states of include/net/tcp_states.h in kernel order.
+var tcpStates = []string{
+	1:  "ESTABLISHED",
+	2:  "SYN_SENT",
+	3:  "SYN_RECV",
+	4:  "FIN_WAIT1",
+	5:  "FIN_WAIT2",
+	6:  "TIME_WAIT",
+	7:  "CLOSE",
+	8:  "CLOSE_WAIT",
+	9:  "LAST_ACK",
+	10: "LISTEN",
+	11: "CLOSING",
+	12: "NEW_SYN_RECV",
+}
+
+type tcpStatesCollector struct {
+	netDir string
+}
+
+func newTCPStatesCollector(procRoot string) *tcpStatesCollector {
+	return &tcpStatesCollector{netDir: netDir(procRoot)}
+}
+
+func (c *tcpStatesCollector) Name() string {
+	return string(entity.CategoryTCPStates)
+}
+
+func (c *tcpStatesCollector) Available() error {
+	return fileAvailable(filepath.Join(c.netDir, "tcp"))
+}
+
+// Collect counts TCP sockets per state. Every state is reported, including
+// empty ones, so that window averages are not skewed by missing samples.
+func (c *tcpStatesCollector) Collect(_ context.Context, m *entity.SystemMetrics) error {
+	var states []entity.TCPStateCount
+	for _, table := range []struct{ file, family string }{
+		{"tcp", "ipv4"},
+		{"tcp6", "ipv6"},
+	} {
+		path := filepath.Join(c.netDir, table.file)
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+		entries, err := parseNetTable(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		states = append(states, countTCPStates(table.family, entries)...)
+	}
+
+	m.TCPStates = states
+	return nil
+}
+
+func countTCPStates(family string, entries []socketEntry) []entity.TCPStateCount {
+	counts := make([]int, len(tcpStates))
+	for _, e := range entries {
+		if int(e.state) < len(counts) {
+			counts[e.state]++
+		}
+	}
+
+	states := make([]entity.TCPStateCount, 0, len(tcpStates)-1)
+	for state := 1; state < len(tcpStates); state++ {
+		states = append(states, entity.TCPStateCount{
+			Family: family,
+			State:  tcpStates[state],
+			Count:  float64(counts[state]),
+		})
+	}
+	return states
+}
@@ -0,0 +1,24 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountTCPStates(t *testing.T) {
+	entries := []socketEntry{{state: 0x01}, {state: 0x01}, {state: 0x08}, {state: 0x0A}, {state: 0x2F}}
+
+	states := countTCPStates("ipv4", entries)
+	require.Len(t, states, 12)
+
+	counts := make(map[string]float64)
+	for _, s := range states {
+		require.Equal(t, "ipv4", s.Family)
+		counts[s.State] = s.Count
+	}
+	require.Equal(t, float64(2), counts["ESTABLISHED"])
+	require.Equal(t, float64(1), counts["CLOSE_WAIT"])
+	require.Equal(t, float64(1), counts["LISTEN"])
+	require.Equal(t, float64(0), counts["TIME_WAIT"])
+}
@@ -0,0 +1,204 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dimryb/system-monitor/internal/capture"
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+)
+
+// testdata/traffic.pcap holds an HTTPS download, DNS over IPv6, a ping, an
+// ARP reply, an IPv6 packet without payload and a GRE packet.
+func replayTraffic(t *testing.T) (*sharedCapture, *protocolsCollector, *talkersCollector) {
+	t.Helper()
+	shared, err := newSharedCapture(config.CaptureConfig{
+		Enabled: true,
+		SnapLen: 256,
+		File:    "testdata/traffic.pcap",
+		Replay:  "fast",
+	})
+	require.NoError(t, err)
+	return shared, newProtocolsCollector("/proc", shared), newTalkersCollector(shared, 3, 100)
+}
+
+func waitReplayed(t *testing.T, shared *sharedCapture) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		shared.mu.Lock()
+		defer shared.mu.Unlock()
+		return shared.finished
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTrafficCollectors_Replay(t *testing.T) {
+	shared, protocols, talkers := replayTraffic(t)
+	ctx := context.Background()
+
+	baseline := &entity.SystemMetrics{}
+	require.NoError(t, protocols.Collect(ctx, baseline))
+	require.NoError(t, talkers.Collect(ctx, baseline))
+	require.Nil(t, baseline.Protocols)
+	require.Nil(t, baseline.TopTalkers)
+	waitReplayed(t, shared)
+
+	m := &entity.SystemMetrics{}
+	require.NoError(t, protocols.Collect(ctx, m))
+	require.NoError(t, talkers.Collect(ctx, m))
+
+	require.Equal(t, entity.TrafficSourceCapture, m.ProtocolSource)
+	bytes := make(map[string]uint64)
+	var percent float64
+	for _, p := range m.Protocols {
+		bytes[p.Protocol] = p.Bytes
+		percent += p.Percent
+	}
+	require.Equal(t, map[string]uint64{
+		protocolTCP:   15280,
+		protocolUDP:   648,
+		protocolICMP:  98,
+		protocolARP:   60,
+		protocolIPv6:  54,
+		protocolOther: 134,
+	}, bytes)
+	require.InDelta(t, 100, percent, 1e-9)
+
+	require.Len(t, m.TopTalkers, 3)
+	require.Equal(t, "93.184.216.34:443", m.TopTalkers[0].Src)
+	require.Equal(t, "10.0.0.5:51000", m.TopTalkers[0].Dst)
+	// Ten 1474-byte segments over the 270 ms between the first and the last
+	// packet of the file, however fast it was replayed.
+	require.InDelta(t, 14740*8/0.27, float64(m.TopTalkers[0].BPS), 1)
+	require.Equal(t, "UDP", m.TopTalkers[1].Protocol)
+	require.Equal(t, "[2001:db8::53]:53", m.TopTalkers[1].Dst)
+	require.Equal(t, "10.0.0.5:51000", m.TopTalkers[2].Src)
+
+	// The file is replayed once.
+	m = &entity.SystemMetrics{}
+	require.NoError(t, protocols.Collect(ctx, m))
+	require.Equal(t, entity.TrafficSourceCapture, m.ProtocolSource)
+	for _, p := range m.Protocols {
+		require.Zero(t, p.Bytes, p.Protocol)
+	}
+}
+
+func TestSharedCapture_BadReplayMode(t *testing.T) {
+	_, err := newSharedCapture(config.CaptureConfig{File: "testdata/traffic.pcap", Replay: "slow"})
+	require.Error(t, err)
+}
+
+type idleSource struct {
+	closed chan struct{}
+}
+
+func (s *idleSource) ReadPacket([]byte) (int, capture.CaptureInfo, error) {
+	time.Sleep(time.Millisecond)
+	return 0, capture.CaptureInfo{}, capture.ErrTimeout
+}
+
+func (s *idleSource) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestSharedCapture_StartStop(t *testing.T) {
+	var sources []*idleSource
+	shared := &sharedCapture{
+		open: func() (capture.Source, error) {
+			s := &idleSource{closed: make(chan struct{})}
+			sources = append(sources, s)
+			return s, nil
+		},
+		users: make(map[string]bool),
+	}
+
+	require.NoError(t, shared.Start("protocols"))
+	require.NoError(t, shared.Start("talkers"))
+	require.NoError(t, shared.Start("talkers"))
+	require.Len(t, sources, 1)
+
+	shared.Stop("protocols")
+	select {
+	case <-sources[0].closed:
+		t.Fatal("capture stopped while still used")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	shared.Stop("talkers")
+	select {
+	case <-sources[0].closed:
+	case <-time.After(time.Second):
+		t.Fatal("capture still running without users")
+	}
+
+	require.NoError(t, shared.Start("talkers"))
+	require.Len(t, sources, 2)
+	shared.Stop("talkers")
+	<-sources[1].closed
+}
+
+func TestSharedCapture_Unavailable(t *testing.T) {
+	opens := 0
+	shared := &sharedCapture{
+		open: func() (capture.Source, error) {
+			opens++
+			return nil, fmt.Errorf("open socket: %w", os.ErrPermission)
+		},
+		users: make(map[string]bool),
+	}
+
+	for range 2 {
+		err := shared.Start("talkers")
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.ErrorIs(t, err, os.ErrPermission)
+		require.EqualError(t, err, "open socket: permission denied")
+	}
+	require.Equal(t, 1, opens)
+}
+
+func TestTalkersCollector_LiveWallClock(t *testing.T) {
+	shared := &sharedCapture{
+		open: func() (capture.Source, error) {
+			return &idleSource{closed: make(chan struct{})}, nil
+		},
+		users: make(map[string]bool),
+	}
+	talkers := newTalkersCollector(shared, 3, 100)
+	defer talkers.Stop()
+	now := time.Unix(1700000000, 0)
+	talkers.now = func() time.Time { return now }
+	observe := func(length int, at time.Time) {
+		talkers.flows.Observe(&capture.Packet{
+			Timestamp: at, Length: length, Transport: capture.TransportUDP,
+			Src: netip.MustParseAddr("10.0.0.1"), SrcPort: 53,
+			Dst: netip.MustParseAddr("10.0.0.2"), DstPort: 40000,
+		})
+	}
+
+	ctx := context.Background()
+	require.NoError(t, talkers.Collect(ctx, &entity.SystemMetrics{}))
+
+	// A single packet, and a burst after an idle spell, are both averaged
+	// over the time between the calls.
+	observe(1000, now.Add(time.Second))
+	now = now.Add(2 * time.Second)
+	m := &entity.SystemMetrics{}
+	require.NoError(t, talkers.Collect(ctx, m))
+	require.Len(t, m.TopTalkers, 1)
+	require.Equal(t, uint64(4000), m.TopTalkers[0].BPS)
+
+	for i := range 10 {
+		observe(1000, now.Add(5*time.Second+time.Duration(i)*time.Millisecond))
+	}
+	now = now.Add(10 * time.Second)
+	require.NoError(t, talkers.Collect(ctx, m))
+	require.Len(t, m.TopTalkers, 1)
+	require.Equal(t, uint64(8000), m.TopTalkers[0].BPS)
+}
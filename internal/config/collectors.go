@@ -0,0 +1,189 @@
+package config
+
+import "time"
+
+const DefaultPeriod = time.Second
+
+type (
+	Collectors struct {
+		Host       Host                `yaml:"host"`
+		LoadAvg    Category            `yaml:"loadavg"`
+		CPU        CPUCollector        `yaml:"cpu"`
+		Memory     Category            `yaml:"memory"`
+		Pressure   Category            `yaml:"pressure"`
+		Cgroups    CgroupsCollector    `yaml:"cgroups"`
+		Disk       DiskCollector       `yaml:"disk"`
+		Fs         FsCollector         `yaml:"fs"`
+		Interfaces InterfacesCollector `yaml:"interfaces"`
+		Sockets    Category            `yaml:"sockets"`
+		TCPStates  Category            `yaml:"tcp_states"`
+		Protocols  Category            `yaml:"protocols"`
+		Capture    CaptureConfig       `yaml:"capture"`
+		Talkers    TalkersCollector    `yaml:"talkers"`
+		Custom     []CustomMetric      `yaml:"custom"`
+	}
+
+	// Host locates the files of the monitored host, e.g. /host/proc when the
+	// daemon runs in a container with the host's procfs mounted elsewhere.
+	// The network tables are then read from the host's init process, as
+	// <proc>/net belongs to the daemon's network namespace. Root is where the host's root filesystem is mounted: the mount points
+	// of the host are resolved under it. Empty values mean /proc, /sys and /.
+	Host struct {
+		Proc string `yaml:"proc" env:"HOST_PROC"`
+		Sys  string `yaml:"sys" env:"HOST_SYS"`
+		Root string `yaml:"root" env:"HOST_ROOT"`
+	}
+
+	// Category holds the settings every metric category has. A category is
+	// enabled unless set otherwise and is collected every Period, one second
+	// by default.
+	Category struct {
+		Enabled *bool         `yaml:"enabled"`
+		Period  time.Duration `yaml:"period"`
+	}
+
+	CPUCollector struct {
+		Category `yaml:",inline"`
+		PerCore  bool `yaml:"per_core" env:"COLLECTOR_CPU_PER_CORE"`
+	}
+
+	// CgroupsCollector reports the Top cgroups by CPU and the Top ones by
+	// memory of the cgroup v2 hierarchy.
+	CgroupsCollector struct {
+		Category `yaml:",inline"`
+		Top      int `yaml:"top" env-default:"10"`
+	}
+
+	// DiskCollector selects devices by regular expressions. When Exclude is not
+	// set, partitions, loop, ram and optical devices are excluded.
+	DiskCollector struct {
+		Category `yaml:",inline"`
+		Include  []string `yaml:"include"`
+		Exclude  []string `yaml:"exclude"`
+	}
+
+	// FsCollector selects filesystems by type. When ExcludeTypes is not set,
+	// pseudo and in-memory filesystems are excluded. StatfsTimeout bounds the
+	// time spent on one mount point, so a hung network mount is skipped.
+	FsCollector struct {
+		Category      `yaml:",inline"`
+		IncludeTypes  []string      `yaml:"include_types"`
+		ExcludeTypes  []string      `yaml:"exclude_types"`
+		StatfsTimeout time.Duration `yaml:"statfs_timeout" env:"COLLECTOR_FS_STATFS_TIMEOUT" env-default:"1s"`
+	}
+
+	// InterfacesCollector selects network interfaces by regular expressions.
+	// When Exclude is not set, the veth pairs of containers and the bridges of
+	// docker are excluded.
+	InterfacesCollector struct {
+		Category `yaml:",inline"`
+		Include  []string `yaml:"include"`
+		Exclude  []string `yaml:"exclude"`
+	}
+
+	// CaptureConfig configures the packet capture shared by the traffic
+	// collectors. It uses an AF_PACKET socket, which needs CAP_NET_RAW, so it
+	// is disabled by default. An empty Interface captures on all interfaces.
+	// File replays a pcap or pcapng file instead, at its original pace
+	// ("realtime") or as fast as possible ("fast").
+	CaptureConfig struct {
+		Enabled   bool   `yaml:"enabled" env:"COLLECTOR_CAPTURE_ENABLED"`
+		Interface string `yaml:"interface" env:"COLLECTOR_CAPTURE_INTERFACE"`
+		SnapLen   int    `yaml:"snap_len" env-default:"256"`
+		File      string `yaml:"file" env:"COLLECTOR_CAPTURE_FILE"`
+		Replay    string `yaml:"replay" env:"COLLECTOR_CAPTURE_REPLAY" env-default:"realtime"`
+	}
+
+	// TalkersCollector reports the top flows seen by the capture. MaxFlows
+	// bounds the memory of the flow table.
+	TalkersCollector struct {
+		Category `yaml:",inline"`
+		Top      int `yaml:"top" env-default:"10"`
+		MaxFlows int `yaml:"max_flows" env-default:"10000"`
+	}
+
+	// CustomMetric is measured by running a command every Period. Command is
+	// the program, run with Args and without a shell, unless Shell is set: the
+	// command line is then run by sh -c. Output is parsed as a single number
+	// ("number", the default), key=value lines ("keyvalue") or a JSON object
+	// ("json"). Timeout defaults to the period and cannot exceed it.
+	CustomMetric struct {
+		Category `yaml:",inline"`
+		Name     string        `yaml:"name"`
+		Command  string        `yaml:"command"`
+		Args     []string      `yaml:"args"`
+		Shell    bool          `yaml:"shell"`
+		Timeout  time.Duration `yaml:"timeout"`
+		Output   string        `yaml:"output"`
+	}
+)
+
+func (h Host) ProcPath() string {
+	return orDefault(h.Proc, "/proc")
+}
+
+func (h Host) SysPath() string {
+	return orDefault(h.Sys, "/sys")
+}
+
+func (h Host) RootPath() string {
+	return orDefault(h.Root, "/")
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func (c Category) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+func (c Category) Interval() time.Duration {
+	if c.Period <= 0 {
+		return DefaultPeriod
+	}
+	return c.Period
+}
+
+var defaultDiskExclude = []string{
+	`^(loop|ram|zram|sr|fd)[0-9]+$`,
+	`^(sd|vd|xvd|hd)[a-z]+[0-9]+$`,
+	`^(nvme[0-9]+n[0-9]+|mmcblk[0-9]+)p[0-9]+$`,
+}
+
+func (d DiskCollector) ExcludePatterns() []string {
+	if d.Exclude == nil {
+		return defaultDiskExclude
+	}
+	return d.Exclude
+}
+
+var defaultInterfaceExclude = []string{
+	`^veth`,
+	`^docker[0-9]+$`,
+	`^br-[0-9a-f]+$`,
+}
+
+func (n InterfacesCollector) ExcludePatterns() []string {
+	if n.Exclude == nil {
+		return defaultInterfaceExclude
+	}
+	return n.Exclude
+}
+
+var defaultFsExcludeTypes = []string{
+	"proc", "sysfs", "tmpfs", "devtmpfs", "devpts", "ramfs", "overlay", "squashfs",
+	"cgroup", "cgroup2", "pstore", "bpf", "tracefs", "debugfs", "securityfs", "selinuxfs",
+	"configfs", "fusectl", "mqueue", "hugetlbfs", "autofs", "binfmt_misc", "rpc_pipefs",
+	"nsfs", "efivarfs", "nfsd",
+}
+
+func (f FsCollector) ExcludedTypes() []string {
+	if f.ExcludeTypes == nil {
+		return defaultFsExcludeTypes
+	}
+	return f.ExcludeTypes
+}
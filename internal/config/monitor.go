@@ -2,8 +2,9 @@ package config
 
 type (
 	MonitorConfig struct {
-		Log  Log  `yaml:"log"`
-		GRPC GRPC `yaml:"grpc"`
+		Log        Log        `yaml:"log"`
+		GRPC       GRPC       `yaml:"grpc"`
+		Collectors Collectors `yaml:"collectors"`
 	}
 )
 
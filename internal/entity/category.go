@@ -0,0 +1,68 @@
+package entity
+
+import "slices"
+
+// Category names the part of a snapshot filled by one collector.
+type Category string
+
+const (
+	CategoryLoadAverage Category = "loadavg"
+	CategoryCPU         Category = "cpu"
+	CategoryMemory      Category = "memory"
+	CategoryPressure    Category = "pressure"
+	CategoryCgroups     Category = "cgroups"
+	CategoryDisk        Category = "disk"
+	CategoryFilesystem  Category = "fs"
+	CategoryInterfaces  Category = "interfaces"
+	CategorySockets     Category = "sockets"
+	CategoryTCPStates   Category = "tcp_states"
+	CategoryProtocols   Category = "protocols"
+	CategoryTalkers     Category = "talkers"
+	// CategoryCustom holds the metrics of the commands configured by the user.
+	CategoryCustom Category = "custom"
+)
+
+// Select returns a copy of m with only the parts and statuses of the given
+// categories.
+func Select(m *SystemMetrics, categories []Category) *SystemMetrics {
+	if m == nil {
+		return nil
+	}
+	selected := &SystemMetrics{Timestamp: m.Timestamp}
+	for _, c := range categories {
+		switch c {
+		case CategoryLoadAverage:
+			selected.LoadAverage = m.LoadAverage
+		case CategoryCPU:
+			selected.CPU, selected.CPUUsagePercent = m.CPU, m.CPUUsagePercent
+		case CategoryMemory:
+			selected.Memory, selected.MemoryUsedMB = m.Memory, m.MemoryUsedMB
+		case CategoryPressure:
+			selected.Pressure = m.Pressure
+		case CategoryCgroups:
+			selected.Cgroups = m.Cgroups
+		case CategoryDisk:
+			selected.Disks = m.Disks
+		case CategoryFilesystem:
+			selected.Filesystems, selected.DiskUsedPercent = m.Filesystems, m.DiskUsedPercent
+		case CategoryInterfaces:
+			selected.Interfaces = m.Interfaces
+		case CategorySockets:
+			selected.Connections = m.Connections
+		case CategoryTCPStates:
+			selected.TCPStates = m.TCPStates
+		case CategoryProtocols:
+			selected.Protocols, selected.ProtocolSource = m.Protocols, m.ProtocolSource
+		case CategoryTalkers:
+			selected.TopTalkers = m.TopTalkers
+		case CategoryCustom:
+			selected.Custom = m.Custom
+		}
+	}
+	for _, status := range m.Statuses {
+		if slices.Contains(categories, status.Category) {
+			selected.Statuses = append(selected.Statuses, status)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	m := &SystemMetrics{
+		Timestamp:       time.Now(),
+		CPUUsagePercent: 25,
+		DiskUsedPercent: 40,
+		LoadAverage:     &LoadAverage{OneMin: 1},
+		CPU:             &CPUUsage{User: 20, System: 5, Idle: 75},
+		Memory:          &MemoryStats{TotalMB: 4096, UsedMB: 1024},
+		MemoryUsedMB:    1024,
+		Pressure:        []PressureStats{{Resource: "cpu", Some: PressureLine{Percent: 12.5, TotalUsec: 900}}},
+		Cgroups:         []CgroupStats{{Path: "/system.slice/sshd.service", CPUPercent: 2, MemoryMB: 6}},
+		Filesystems:     []FsUsage{{MountPoint: "/", UsedPercent: 40}},
+		Interfaces:      []InterfaceStats{{Name: "eth0", State: "up", RxBytesPerSec: 1200}},
+		Protocols:       []ProtocolTraffic{{Protocol: "TCP", Bytes: 100, Percent: 100}},
+		ProtocolSource:  TrafficSourceProcfs,
+		TopTalkers:      []TopTalker{{Src: "10.0.0.1:443", Dst: "10.0.0.2:5000", Protocol: "TCP", BPS: 800}},
+		Custom:          []CustomMetric{{Name: "queue", Values: []CustomValue{{Value: 12}}, State: CollectionOK}},
+		Statuses: []CollectionStatus{
+			{Category: CategoryCPU, State: CollectionOK},
+			{Category: CategoryTalkers, State: CollectionUnavailable, Message: "operation not permitted"},
+		},
+	}
+
+	require.Equal(t, &SystemMetrics{
+		Timestamp:       m.Timestamp,
+		CPUUsagePercent: 25,
+		CPU:             m.CPU,
+		Protocols:       m.Protocols,
+		ProtocolSource:  TrafficSourceProcfs,
+		Statuses:        []CollectionStatus{{Category: CategoryCPU, State: CollectionOK}},
+	}, Select(m, []Category{CategoryCPU, CategoryProtocols}))
+
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp, Memory: m.Memory, MemoryUsedMB: 1024},
+		Select(m, []Category{CategoryMemory}))
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp, Pressure: m.Pressure},
+		Select(m, []Category{CategoryPressure}))
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp, Cgroups: m.Cgroups},
+		Select(m, []Category{CategoryCgroups}))
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp, Interfaces: m.Interfaces},
+		Select(m, []Category{CategoryInterfaces}))
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp, Custom: m.Custom}, Select(m, []Category{CategoryCustom}))
+	require.Equal(t, &SystemMetrics{Timestamp: m.Timestamp}, Select(m, []Category{CategoryDisk}))
+	require.Nil(t, Select(nil, []Category{CategoryCPU}))
+}
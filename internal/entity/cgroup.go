@@ -0,0 +1,15 @@
+package entity
+
+// CgroupStats holds the resources used by a cgroup v2, such as a systemd unit
+// or a container. Path is relative to the root of the hierarchy, as in
+// /proc/<pid>/cgroup. CPUPercent is 100 for one fully used CPU, as top(1)
+// reports it. MemoryLimitMB is zero when the memory is not limited.
+type CgroupStats struct {
+	Path            string
+	CPUPercent      float64
+	MemoryMB        uint64
+	MemoryLimitMB   uint64
+	IOReadKBPerSec  float64
+	IOWriteKBPerSec float64
+	Pids            uint64
+}
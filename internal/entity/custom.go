@@ -0,0 +1,20 @@
+package entity
+
+// CustomMetric is the result of the latest run of a command configured by the
+// user. A failed run has no values: Message tells why, ExitCode and Stderr
+// come from the command when it ran.
+type CustomMetric struct {
+	Name     string
+	Values   []CustomValue
+	State    CollectionState
+	Message  string
+	ExitCode int32
+	Stderr   string
+}
+
+// CustomValue is a number of the command output. Key is empty when the output
+// is a single number.
+type CustomValue struct {
+	Key   string
+	Value float64
+}
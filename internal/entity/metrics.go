@@ -8,4 +8,21 @@ type SystemMetrics struct {
 	CPUUsagePercent float64
 	MemoryUsedMB    uint64
 	DiskUsedPercent float64
+
+	LoadAverage    *LoadAverage
+	CPU            *CPUUsage
+	Memory         *MemoryStats
+	Pressure       []PressureStats
+	Cgroups        []CgroupStats
+	Disks          []DiskStats
+	Filesystems    []FsUsage
+	Interfaces     []InterfaceStats
+	Protocols      []ProtocolTraffic
+	ProtocolSource TrafficSource
+	Connections    []NetworkConnection
+	TopTalkers     []TopTalker
+	TCPStates      []TCPStateCount
+	Custom         []CustomMetric
+
+	Statuses []CollectionStatus
 }
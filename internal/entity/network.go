@@ -0,0 +1,56 @@
+package entity
+
+// TrafficSource tells how ProtocolTraffic was measured: from captured packets
+// or, without capture privileges, estimated from kernel counters.
+type TrafficSource string
+
+const (
+	TrafficSourceCapture TrafficSource = "capture"
+	TrafficSourceProcfs  TrafficSource = "procfs"
+)
+
+// ProtocolTraffic.Bytes is the amount of traffic seen since the previous sample.
+type ProtocolTraffic struct {
+	Protocol string
+	Bytes    uint64
+	Percent  float64
+}
+
+// InterfaceStats holds the traffic of a network interface per second. State is
+// the operational state reported by the kernel ("up", "down", "unknown"...),
+// SpeedMbps is zero when the link speed is not known.
+type InterfaceStats struct {
+	Name            string
+	State           string
+	SpeedMbps       uint64
+	RxBytesPerSec   float64
+	TxBytesPerSec   float64
+	RxPacketsPerSec float64
+	TxPacketsPerSec float64
+	RxErrorsPerSec  float64
+	TxErrorsPerSec  float64
+	RxDropsPerSec   float64
+	TxDropsPerSec   float64
+}
+
+type NetworkConnection struct {
+	Command  string
+	PID      int32
+	User     string
+	Protocol string
+	Port     int32
+}
+
+type TopTalker struct {
+	Src      string
+	Dst      string
+	Protocol string
+	BPS      uint64
+}
+
+// TCPStateCount is the number of TCP sockets of an address family in a state.
+type TCPStateCount struct {
+	Family string
+	State  string
+	Count  float64
+}
@@ -1,5 +0,0 @@
-package entity
-
-type Parameter struct {
-	Command string
-}
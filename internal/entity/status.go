@@ -0,0 +1,20 @@
+package entity
+
+// CollectionState tells whether the part of a snapshot could be measured.
+type CollectionState string
+
+const (
+	CollectionOK CollectionState = "ok"
+	// CollectionUnavailable means the host does not allow the measurement,
+	// e.g. packet capture without CAP_NET_RAW.
+	CollectionUnavailable CollectionState = "unavailable"
+	// CollectionError means the latest collection failed.
+	CollectionError CollectionState = "error"
+)
+
+// CollectionStatus is the outcome of the latest collection of a category.
+type CollectionStatus struct {
+	Category Category
+	State    CollectionState
+	Message  string
+}
@@ -0,0 +1,17 @@
+package entity
+
+type DiskStats struct {
+	Device   string
+	TPS      float64
+	KBPerSec float64
+}
+
+type FsUsage struct {
+	MountPoint        string
+	TotalMB           uint64
+	UsedMB            uint64
+	UsedPercent       float64
+	InodesTotal       uint64
+	InodesUsed        uint64
+	InodesUsedPercent float64
+}
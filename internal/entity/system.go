@@ -0,0 +1,70 @@
+package entity
+
+type LoadAverage struct {
+	OneMin       float64
+	FiveMins     float64
+	FifteenMins  float64
+	RunningTasks uint32
+	TotalTasks   uint32
+}
+
+// CPUUsage holds the share of CPU time, in percent, spent in each state.
+type CPUUsage struct {
+	User    float64
+	System  float64
+	Idle    float64
+	Nice    float64
+	IOWait  float64
+	IRQ     float64
+	SoftIRQ float64
+	Steal   float64
+	Cores   []CPUCoreUsage
+}
+
+// MemoryStats holds memory and swap usage in megabytes, "used" counted as by
+// free(1): what is not available to new applications. The swap rates are in
+// kilobytes per second.
+type MemoryStats struct {
+	TotalMB         uint64
+	AvailableMB     uint64
+	UsedMB          uint64
+	BuffCacheMB     uint64
+	DirtyMB         uint64
+	SwapTotalMB     uint64
+	SwapUsedMB      uint64
+	SwapInKBPerSec  float64
+	SwapOutKBPerSec float64
+}
+
+// PressureStats holds the pressure stall information of a resource: cpu,
+// memory or io. Some is the time at least one task was stalled on the
+// resource, Full the time all non-idle tasks were stalled at once.
+type PressureStats struct {
+	Resource string
+	Some     PressureLine
+	Full     PressureLine
+}
+
+// PressureLine holds the share of time, in percent, tasks were stalled.
+// Percent is measured over the collection interval from TotalUsec, the
+// cumulative stall time; Avg10, Avg60 and Avg300 are the running averages of
+// the kernel.
+type PressureLine struct {
+	Percent   float64
+	Avg10     float64
+	Avg60     float64
+	Avg300    float64
+	TotalUsec uint64
+}
+
+type CPUCoreUsage struct {
+	Core    string
+	User    float64
+	System  float64
+	Idle    float64
+	Nice    float64
+	IOWait  float64
+	IRQ     float64
+	SoftIRQ float64
+	Steal   float64
+}
@@ -6,6 +6,25 @@ import (
 	"github.com/dimryb/system-monitor/internal/entity"
 )
 
+// Collector fills its part of a snapshot.
 type Collector interface {
-	Collect(ctx context.Context) (*entity.SystemMetrics, error)
+	Name() string
+	// Available returns why the collector cannot work on this host, or nil.
+	Available() error
+	Collect(ctx context.Context, m *entity.SystemMetrics) error
+}
+
+// Stopper is implemented by collectors that hold resources between calls,
+// such as a packet capture. Stop is called once the collector is no longer
+// scheduled; the next Collect acquires them again.
+type Stopper interface {
+	Stop()
+}
+
+// RateCollector is implemented by collectors reporting rates between two
+// calls. The first call after the collector is scheduled only sets the
+// baseline, its reading would cover the time the collector was stopped and is
+// not published. The readings of other collectors are published right away.
+type RateCollector interface {
+	ReportsRates()
 }
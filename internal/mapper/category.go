@@ -0,0 +1,66 @@
+package mapper
+
+import (
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/dimryb/system-monitor/proto/monitor"
+)
+
+var categories = map[monitor.MetricCategory]entity.Category{
+	monitor.MetricCategory_METRIC_CATEGORY_LOAD_AVERAGE:     entity.CategoryLoadAverage,
+	monitor.MetricCategory_METRIC_CATEGORY_CPU:              entity.CategoryCPU,
+	monitor.MetricCategory_METRIC_CATEGORY_DISK:             entity.CategoryDisk,
+	monitor.MetricCategory_METRIC_CATEGORY_FILESYSTEM:       entity.CategoryFilesystem,
+	monitor.MetricCategory_METRIC_CATEGORY_CONNECTIONS:      entity.CategorySockets,
+	monitor.MetricCategory_METRIC_CATEGORY_TCP_STATES:       entity.CategoryTCPStates,
+	monitor.MetricCategory_METRIC_CATEGORY_PROTOCOL_TRAFFIC: entity.CategoryProtocols,
+	monitor.MetricCategory_METRIC_CATEGORY_TOP_TALKERS:      entity.CategoryTalkers,
+	monitor.MetricCategory_METRIC_CATEGORY_CUSTOM:           entity.CategoryCustom,
+	monitor.MetricCategory_METRIC_CATEGORY_MEMORY:           entity.CategoryMemory,
+	monitor.MetricCategory_METRIC_CATEGORY_INTERFACES:       entity.CategoryInterfaces,
+	monitor.MetricCategory_METRIC_CATEGORY_PRESSURE:         entity.CategoryPressure,
+	monitor.MetricCategory_METRIC_CATEGORY_CGROUPS:          entity.CategoryCgroups,
+}
+
+// FromCategory returns the collector category of a requested one. It reports
+// false for METRIC_CATEGORY_UNSPECIFIED and unknown values.
+func FromCategory(c monitor.MetricCategory) (entity.Category, bool) {
+	category, ok := categories[c]
+	return category, ok
+}
+
+// ToCategory returns the protocol value of a collector category, or
+// METRIC_CATEGORY_UNSPECIFIED for an unknown one.
+func ToCategory(c entity.Category) monitor.MetricCategory {
+	for value, category := range categories {
+		if category == c {
+			return value
+		}
+	}
+	return monitor.MetricCategory_METRIC_CATEGORY_UNSPECIFIED
+}
+
+func toCollectionState(state entity.CollectionState) monitor.CollectionState {
+	switch state {
+	case entity.CollectionOK:
+		return monitor.CollectionState_COLLECTION_STATE_OK
+	case entity.CollectionUnavailable:
+		return monitor.CollectionState_COLLECTION_STATE_UNAVAILABLE
+	case entity.CollectionError:
+		return monitor.CollectionState_COLLECTION_STATE_ERROR
+	default:
+		return monitor.CollectionState_COLLECTION_STATE_UNSPECIFIED
+	}
+}
+
+func fromCollectionState(state monitor.CollectionState) entity.CollectionState {
+	switch state {
+	case monitor.CollectionState_COLLECTION_STATE_OK:
+		return entity.CollectionOK
+	case monitor.CollectionState_COLLECTION_STATE_UNAVAILABLE:
+		return entity.CollectionUnavailable
+	case monitor.CollectionState_COLLECTION_STATE_ERROR:
+		return entity.CollectionError
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,368 @@
+package mapper
+
+import (
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/dimryb/system-monitor/proto/monitor"
+)
+
+func ToSnapshot(m *entity.SystemMetrics) *monitor.SystemSnapshot {
+	snapshot := &monitor.SystemSnapshot{}
+	if m == nil {
+		return snapshot
+	}
+
+	if m.LoadAverage != nil {
+		snapshot.LoadAvg = &monitor.LoadAverage{
+			OneMin:       m.LoadAverage.OneMin,
+			FiveMins:     m.LoadAverage.FiveMins,
+			FifteenMins:  m.LoadAverage.FifteenMins,
+			RunningTasks: m.LoadAverage.RunningTasks,
+			TotalTasks:   m.LoadAverage.TotalTasks,
+		}
+	}
+	if m.CPU != nil {
+		snapshot.CpuUsage = &monitor.CpuUsage{
+			User:    m.CPU.User,
+			System:  m.CPU.System,
+			Idle:    m.CPU.Idle,
+			Nice:    m.CPU.Nice,
+			Iowait:  m.CPU.IOWait,
+			Irq:     m.CPU.IRQ,
+			Softirq: m.CPU.SoftIRQ,
+			Steal:   m.CPU.Steal,
+		}
+		for _, c := range m.CPU.Cores {
+			snapshot.CpuUsage.Cores = append(snapshot.CpuUsage.Cores, &monitor.CpuCoreUsage{
+				Core:    c.Core,
+				User:    c.User,
+				System:  c.System,
+				Idle:    c.Idle,
+				Nice:    c.Nice,
+				Iowait:  c.IOWait,
+				Irq:     c.IRQ,
+				Softirq: c.SoftIRQ,
+				Steal:   c.Steal,
+			})
+		}
+	}
+	if m.Memory != nil {
+		snapshot.Memory = &monitor.MemoryStats{
+			TotalMb:         m.Memory.TotalMB,
+			AvailableMb:     m.Memory.AvailableMB,
+			UsedMb:          m.Memory.UsedMB,
+			BuffCacheMb:     m.Memory.BuffCacheMB,
+			DirtyMb:         m.Memory.DirtyMB,
+			SwapTotalMb:     m.Memory.SwapTotalMB,
+			SwapUsedMb:      m.Memory.SwapUsedMB,
+			SwapInKbPerSec:  m.Memory.SwapInKBPerSec,
+			SwapOutKbPerSec: m.Memory.SwapOutKBPerSec,
+		}
+	}
+	for _, p := range m.Pressure {
+		snapshot.Pressure = append(snapshot.Pressure, &monitor.PressureStats{
+			Resource: p.Resource,
+			Some:     toPressureLine(p.Some),
+			Full:     toPressureLine(p.Full),
+		})
+	}
+	for _, g := range m.Cgroups {
+		snapshot.Cgroups = append(snapshot.Cgroups, &monitor.CgroupStats{
+			Path:            g.Path,
+			CpuPercent:      g.CPUPercent,
+			MemoryMb:        g.MemoryMB,
+			MemoryLimitMb:   g.MemoryLimitMB,
+			IoReadKbPerSec:  g.IOReadKBPerSec,
+			IoWriteKbPerSec: g.IOWriteKBPerSec,
+			Pids:            g.Pids,
+		})
+	}
+	for _, d := range m.Disks {
+		snapshot.DiskStats = append(snapshot.DiskStats, &monitor.DiskStats{
+			Device:   d.Device,
+			Tps:      d.TPS,
+			KbPerSec: d.KBPerSec,
+		})
+	}
+	for _, f := range m.Filesystems {
+		snapshot.FsUsage = append(snapshot.FsUsage, &monitor.FsUsage{
+			MountPoint:        f.MountPoint,
+			TotalMb:           f.TotalMB,
+			UsedMb:            f.UsedMB,
+			UsedPercent:       f.UsedPercent,
+			InodesTotal:       f.InodesTotal,
+			InodesUsed:        f.InodesUsed,
+			InodesUsedPercent: f.InodesUsedPercent,
+		})
+	}
+	for _, n := range m.Interfaces {
+		snapshot.Interfaces = append(snapshot.Interfaces, &monitor.InterfaceStats{
+			Name:            n.Name,
+			State:           n.State,
+			SpeedMbps:       n.SpeedMbps,
+			RxBytesPerSec:   n.RxBytesPerSec,
+			TxBytesPerSec:   n.TxBytesPerSec,
+			RxPacketsPerSec: n.RxPacketsPerSec,
+			TxPacketsPerSec: n.TxPacketsPerSec,
+			RxErrorsPerSec:  n.RxErrorsPerSec,
+			TxErrorsPerSec:  n.TxErrorsPerSec,
+			RxDropsPerSec:   n.RxDropsPerSec,
+			TxDropsPerSec:   n.TxDropsPerSec,
+		})
+	}
+	for _, p := range m.Protocols {
+		snapshot.ProtocolTraffic = append(snapshot.ProtocolTraffic, &monitor.ProtocolTraffic{
+			Protocol: p.Protocol,
+			Bytes:    p.Bytes,
+			Percent:  p.Percent,
+		})
+	}
+	snapshot.ProtocolTrafficSource = toTrafficSource(m.ProtocolSource)
+	for _, c := range m.Connections {
+		snapshot.Connections = append(snapshot.Connections, &monitor.NetworkConnection{
+			Command:  c.Command,
+			Pid:      c.PID,
+			User:     c.User,
+			Protocol: c.Protocol,
+			Port:     c.Port,
+		})
+	}
+	for _, t := range m.TopTalkers {
+		snapshot.TopTalkers = append(snapshot.TopTalkers, &monitor.TopTalker{
+			Src:      t.Src,
+			Dst:      t.Dst,
+			Protocol: t.Protocol,
+			Bps:      t.BPS,
+		})
+	}
+	for _, s := range m.TCPStates {
+		snapshot.TcpStates = append(snapshot.TcpStates, &monitor.TcpStateCount{
+			Family: s.Family,
+			State:  s.State,
+			Count:  s.Count,
+		})
+	}
+	for _, c := range m.Custom {
+		metric := &monitor.CustomMetric{
+			Name:     c.Name,
+			State:    toCollectionState(c.State),
+			Message:  c.Message,
+			ExitCode: c.ExitCode,
+			Stderr:   c.Stderr,
+		}
+		for _, v := range c.Values {
+			metric.Values = append(metric.Values, &monitor.CustomValue{Key: v.Key, Value: v.Value})
+		}
+		snapshot.CustomMetrics = append(snapshot.CustomMetrics, metric)
+	}
+	for _, st := range m.Statuses {
+		snapshot.Statuses = append(snapshot.Statuses, &monitor.CollectionStatus{
+			Category: ToCategory(st.Category),
+			State:    toCollectionState(st.State),
+			Message:  st.Message,
+		})
+	}
+	return snapshot
+}
+
+func FromSnapshot(s *monitor.SystemSnapshot) *entity.SystemMetrics {
+	m := &entity.SystemMetrics{}
+	if s == nil {
+		return m
+	}
+
+	if l := s.GetLoadAvg(); l != nil {
+		m.LoadAverage = &entity.LoadAverage{
+			OneMin:       l.GetOneMin(),
+			FiveMins:     l.GetFiveMins(),
+			FifteenMins:  l.GetFifteenMins(),
+			RunningTasks: l.GetRunningTasks(),
+			TotalTasks:   l.GetTotalTasks(),
+		}
+	}
+	if c := s.GetCpuUsage(); c != nil {
+		m.CPU = &entity.CPUUsage{
+			User:    c.GetUser(),
+			System:  c.GetSystem(),
+			Idle:    c.GetIdle(),
+			Nice:    c.GetNice(),
+			IOWait:  c.GetIowait(),
+			IRQ:     c.GetIrq(),
+			SoftIRQ: c.GetSoftirq(),
+			Steal:   c.GetSteal(),
+		}
+		for _, core := range c.GetCores() {
+			m.CPU.Cores = append(m.CPU.Cores, entity.CPUCoreUsage{
+				Core:    core.GetCore(),
+				User:    core.GetUser(),
+				System:  core.GetSystem(),
+				Idle:    core.GetIdle(),
+				Nice:    core.GetNice(),
+				IOWait:  core.GetIowait(),
+				IRQ:     core.GetIrq(),
+				SoftIRQ: core.GetSoftirq(),
+				Steal:   core.GetSteal(),
+			})
+		}
+	}
+	if mem := s.GetMemory(); mem != nil {
+		m.Memory = &entity.MemoryStats{
+			TotalMB:         mem.GetTotalMb(),
+			AvailableMB:     mem.GetAvailableMb(),
+			UsedMB:          mem.GetUsedMb(),
+			BuffCacheMB:     mem.GetBuffCacheMb(),
+			DirtyMB:         mem.GetDirtyMb(),
+			SwapTotalMB:     mem.GetSwapTotalMb(),
+			SwapUsedMB:      mem.GetSwapUsedMb(),
+			SwapInKBPerSec:  mem.GetSwapInKbPerSec(),
+			SwapOutKBPerSec: mem.GetSwapOutKbPerSec(),
+		}
+		m.MemoryUsedMB = m.Memory.UsedMB
+	}
+	for _, p := range s.GetPressure() {
+		m.Pressure = append(m.Pressure, entity.PressureStats{
+			Resource: p.GetResource(),
+			Some:     fromPressureLine(p.GetSome()),
+			Full:     fromPressureLine(p.GetFull()),
+		})
+	}
+	for _, g := range s.GetCgroups() {
+		m.Cgroups = append(m.Cgroups, entity.CgroupStats{
+			Path:            g.GetPath(),
+			CPUPercent:      g.GetCpuPercent(),
+			MemoryMB:        g.GetMemoryMb(),
+			MemoryLimitMB:   g.GetMemoryLimitMb(),
+			IOReadKBPerSec:  g.GetIoReadKbPerSec(),
+			IOWriteKBPerSec: g.GetIoWriteKbPerSec(),
+			Pids:            g.GetPids(),
+		})
+	}
+	for _, d := range s.GetDiskStats() {
+		m.Disks = append(m.Disks, entity.DiskStats{
+			Device:   d.GetDevice(),
+			TPS:      d.GetTps(),
+			KBPerSec: d.GetKbPerSec(),
+		})
+	}
+	for _, f := range s.GetFsUsage() {
+		m.Filesystems = append(m.Filesystems, entity.FsUsage{
+			MountPoint:        f.GetMountPoint(),
+			TotalMB:           f.GetTotalMb(),
+			UsedMB:            f.GetUsedMb(),
+			UsedPercent:       f.GetUsedPercent(),
+			InodesTotal:       f.GetInodesTotal(),
+			InodesUsed:        f.GetInodesUsed(),
+			InodesUsedPercent: f.GetInodesUsedPercent(),
+		})
+	}
+	for _, n := range s.GetInterfaces() {
+		m.Interfaces = append(m.Interfaces, entity.InterfaceStats{
+			Name:            n.GetName(),
+			State:           n.GetState(),
+			SpeedMbps:       n.GetSpeedMbps(),
+			RxBytesPerSec:   n.GetRxBytesPerSec(),
+			TxBytesPerSec:   n.GetTxBytesPerSec(),
+			RxPacketsPerSec: n.GetRxPacketsPerSec(),
+			TxPacketsPerSec: n.GetTxPacketsPerSec(),
+			RxErrorsPerSec:  n.GetRxErrorsPerSec(),
+			TxErrorsPerSec:  n.GetTxErrorsPerSec(),
+			RxDropsPerSec:   n.GetRxDropsPerSec(),
+			TxDropsPerSec:   n.GetTxDropsPerSec(),
+		})
+	}
+	for _, p := range s.GetProtocolTraffic() {
+		m.Protocols = append(m.Protocols, entity.ProtocolTraffic{
+			Protocol: p.GetProtocol(),
+			Bytes:    p.GetBytes(),
+			Percent:  p.GetPercent(),
+		})
+	}
+	m.ProtocolSource = fromTrafficSource(s.GetProtocolTrafficSource())
+	for _, c := range s.GetConnections() {
+		m.Connections = append(m.Connections, entity.NetworkConnection{
+			Command:  c.GetCommand(),
+			PID:      c.GetPid(),
+			User:     c.GetUser(),
+			Protocol: c.GetProtocol(),
+			Port:     c.GetPort(),
+		})
+	}
+	for _, t := range s.GetTopTalkers() {
+		m.TopTalkers = append(m.TopTalkers, entity.TopTalker{
+			Src:      t.GetSrc(),
+			Dst:      t.GetDst(),
+			Protocol: t.GetProtocol(),
+			BPS:      t.GetBps(),
+		})
+	}
+	for _, st := range s.GetTcpStates() {
+		m.TCPStates = append(m.TCPStates, entity.TCPStateCount{
+			Family: st.GetFamily(),
+			State:  st.GetState(),
+			Count:  st.GetCount(),
+		})
+	}
+	for _, c := range s.GetCustomMetrics() {
+		metric := entity.CustomMetric{
+			Name:     c.GetName(),
+			State:    fromCollectionState(c.GetState()),
+			Message:  c.GetMessage(),
+			ExitCode: c.GetExitCode(),
+			Stderr:   c.GetStderr(),
+		}
+		for _, v := range c.GetValues() {
+			metric.Values = append(metric.Values, entity.CustomValue{Key: v.GetKey(), Value: v.GetValue()})
+		}
+		m.Custom = append(m.Custom, metric)
+	}
+	for _, st := range s.GetStatuses() {
+		category, _ := FromCategory(st.GetCategory())
+		m.Statuses = append(m.Statuses, entity.CollectionStatus{
+			Category: category,
+			State:    fromCollectionState(st.GetState()),
+			Message:  st.GetMessage(),
+		})
+	}
+	return m
+}
+
+func toPressureLine(l entity.PressureLine) *monitor.PressureLine {
+	return &monitor.PressureLine{
+		Percent:   l.Percent,
+		Avg10:     l.Avg10,
+		Avg60:     l.Avg60,
+		Avg300:    l.Avg300,
+		TotalUsec: l.TotalUsec,
+	}
+}
+
+func fromPressureLine(l *monitor.PressureLine) entity.PressureLine {
+	return entity.PressureLine{
+		Percent:   l.GetPercent(),
+		Avg10:     l.GetAvg10(),
+		Avg60:     l.GetAvg60(),
+		Avg300:    l.GetAvg300(),
+		TotalUsec: l.GetTotalUsec(),
+	}
+}
+
+func toTrafficSource(source entity.TrafficSource) monitor.TrafficSource {
+	switch source {
+	case entity.TrafficSourceCapture:
+		return monitor.TrafficSource_TRAFFIC_SOURCE_CAPTURE
+	case entity.TrafficSourceProcfs:
+		return monitor.TrafficSource_TRAFFIC_SOURCE_PROCFS
+	default:
+		return monitor.TrafficSource_TRAFFIC_SOURCE_UNSPECIFIED
+	}
+}
+
+func fromTrafficSource(source monitor.TrafficSource) entity.TrafficSource {
+	switch source {
+	case monitor.TrafficSource_TRAFFIC_SOURCE_CAPTURE:
+		return entity.TrafficSourceCapture
+	case monitor.TrafficSource_TRAFFIC_SOURCE_PROCFS:
+		return entity.TrafficSourceProcfs
+	default:
+		return ""
+	}
+}
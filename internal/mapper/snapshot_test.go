@@ -0,0 +1,208 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/dimryb/system-monitor/proto/monitor"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func fullMetrics() *entity.SystemMetrics {
+	return &entity.SystemMetrics{
+		LoadAverage: &entity.LoadAverage{
+			OneMin: 0.5, FiveMins: 0.75, FifteenMins: 1.25, RunningTasks: 2, TotalTasks: 312,
+		},
+		CPU: &entity.CPUUsage{
+			User: 12.5, System: 7.5, Idle: 70, Nice: 1, IOWait: 4, IRQ: 0.5, SoftIRQ: 1.5, Steal: 3,
+			Cores: []entity.CPUCoreUsage{
+				{Core: "cpu0", User: 20, System: 10, Idle: 60, IOWait: 5, Steal: 5},
+				{Core: "cpu1", User: 5, System: 5, Idle: 80, Nice: 2, IRQ: 1, SoftIRQ: 3, IOWait: 3, Steal: 1},
+			},
+		},
+		Memory: &entity.MemoryStats{
+			TotalMB: 7950, AvailableMB: 5480, UsedMB: 2470, BuffCacheMB: 4316, DirtyMB: 2,
+			SwapTotalMB: 2047, SwapUsedMB: 512, SwapInKBPerSec: 16, SwapOutKBPerSec: 4.5,
+		},
+		MemoryUsedMB: 2470,
+		Pressure: []entity.PressureStats{
+			{
+				Resource: "io",
+				Some:     entity.PressureLine{Percent: 5, Avg10: 4.95, Avg60: 1.6, Avg300: 0.53, TotalUsec: 45928901},
+				Full:     entity.PressureLine{Percent: 3, Avg10: 2.95, TotalUsec: 34717890},
+			},
+		},
+		Cgroups: []entity.CgroupStats{
+			{
+				Path: "/system.slice/postgresql.service", CPUPercent: 800, MemoryMB: 24576, MemoryLimitMB: 32768,
+				IOReadKBPerSec: 30720, IOWriteKBPerSec: 15360, Pids: 142,
+			},
+		},
+		Disks: []entity.DiskStats{
+			{Device: "sda", TPS: 12, KBPerSec: 512.5},
+			{Device: "nvme0n1", TPS: 3, KBPerSec: 64},
+		},
+		Filesystems: []entity.FsUsage{
+			{
+				MountPoint: "/", TotalMB: 10240, UsedMB: 2048, UsedPercent: 20,
+				InodesTotal: 655360, InodesUsed: 65536, InodesUsedPercent: 10,
+			},
+		},
+		Interfaces: []entity.InterfaceStats{
+			{
+				Name: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 12500, TxBytesPerSec: 3400,
+				RxPacketsPerSec: 90, TxPacketsPerSec: 45, RxErrorsPerSec: 0.5, RxDropsPerSec: 1.5, TxDropsPerSec: 2,
+			},
+		},
+		Protocols: []entity.ProtocolTraffic{
+			{Protocol: "TCP", Bytes: 7500, Percent: 75},
+			{Protocol: "UDP", Bytes: 2500, Percent: 25},
+		},
+		ProtocolSource: entity.TrafficSourceCapture,
+		Connections: []entity.NetworkConnection{
+			{Command: "sshd", PID: 812, User: "root", Protocol: "tcp", Port: 22},
+		},
+		TopTalkers: []entity.TopTalker{
+			{Src: "10.0.0.1:443", Dst: "10.0.0.2:51234", Protocol: "TCP", BPS: 80000},
+		},
+		TCPStates: []entity.TCPStateCount{
+			{Family: "ipv4", State: "ESTABLISHED", Count: 12.5},
+			{Family: "ipv6", State: "CLOSE_WAIT", Count: 3},
+		},
+		Custom: []entity.CustomMetric{
+			{
+				Name:   "queue",
+				Values: []entity.CustomValue{{Key: "active", Value: 12}, {Key: "deferred", Value: 3}},
+				State:  entity.CollectionOK,
+			},
+			{Name: "backups", State: entity.CollectionError, Message: "exit status 2", ExitCode: 2, Stderr: "no such job"},
+		},
+		Statuses: []entity.CollectionStatus{
+			{Category: entity.CategoryProtocols, State: entity.CollectionOK},
+			{Category: entity.CategoryTalkers, State: entity.CollectionUnavailable, Message: "operation not permitted"},
+		},
+	}
+}
+
+func TestToSnapshot(t *testing.T) {
+	snapshot := ToSnapshot(fullMetrics())
+
+	expected := &monitor.SystemSnapshot{
+		LoadAvg: &monitor.LoadAverage{
+			OneMin: 0.5, FiveMins: 0.75, FifteenMins: 1.25, RunningTasks: 2, TotalTasks: 312,
+		},
+		CpuUsage: &monitor.CpuUsage{
+			User: 12.5, System: 7.5, Idle: 70, Nice: 1, Iowait: 4, Irq: 0.5, Softirq: 1.5, Steal: 3,
+			Cores: []*monitor.CpuCoreUsage{
+				{Core: "cpu0", User: 20, System: 10, Idle: 60, Iowait: 5, Steal: 5},
+				{Core: "cpu1", User: 5, System: 5, Idle: 80, Nice: 2, Irq: 1, Softirq: 3, Iowait: 3, Steal: 1},
+			},
+		},
+		Memory: &monitor.MemoryStats{
+			TotalMb: 7950, AvailableMb: 5480, UsedMb: 2470, BuffCacheMb: 4316, DirtyMb: 2,
+			SwapTotalMb: 2047, SwapUsedMb: 512, SwapInKbPerSec: 16, SwapOutKbPerSec: 4.5,
+		},
+		Pressure: []*monitor.PressureStats{
+			{
+				Resource: "io",
+				Some:     &monitor.PressureLine{Percent: 5, Avg10: 4.95, Avg60: 1.6, Avg300: 0.53, TotalUsec: 45928901},
+				Full:     &monitor.PressureLine{Percent: 3, Avg10: 2.95, TotalUsec: 34717890},
+			},
+		},
+		Cgroups: []*monitor.CgroupStats{
+			{
+				Path: "/system.slice/postgresql.service", CpuPercent: 800, MemoryMb: 24576, MemoryLimitMb: 32768,
+				IoReadKbPerSec: 30720, IoWriteKbPerSec: 15360, Pids: 142,
+			},
+		},
+		DiskStats: []*monitor.DiskStats{
+			{Device: "sda", Tps: 12, KbPerSec: 512.5},
+			{Device: "nvme0n1", Tps: 3, KbPerSec: 64},
+		},
+		FsUsage: []*monitor.FsUsage{
+			{
+				MountPoint: "/", TotalMb: 10240, UsedMb: 2048, UsedPercent: 20,
+				InodesTotal: 655360, InodesUsed: 65536, InodesUsedPercent: 10,
+			},
+		},
+		Interfaces: []*monitor.InterfaceStats{
+			{
+				Name: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 12500, TxBytesPerSec: 3400,
+				RxPacketsPerSec: 90, TxPacketsPerSec: 45, RxErrorsPerSec: 0.5, RxDropsPerSec: 1.5, TxDropsPerSec: 2,
+			},
+		},
+		ProtocolTraffic: []*monitor.ProtocolTraffic{
+			{Protocol: "TCP", Bytes: 7500, Percent: 75},
+			{Protocol: "UDP", Bytes: 2500, Percent: 25},
+		},
+		ProtocolTrafficSource: monitor.TrafficSource_TRAFFIC_SOURCE_CAPTURE,
+		Connections: []*monitor.NetworkConnection{
+			{Command: "sshd", Pid: 812, User: "root", Protocol: "tcp", Port: 22},
+		},
+		TopTalkers: []*monitor.TopTalker{
+			{Src: "10.0.0.1:443", Dst: "10.0.0.2:51234", Protocol: "TCP", Bps: 80000},
+		},
+		TcpStates: []*monitor.TcpStateCount{
+			{Family: "ipv4", State: "ESTABLISHED", Count: 12.5},
+			{Family: "ipv6", State: "CLOSE_WAIT", Count: 3},
+		},
+		CustomMetrics: []*monitor.CustomMetric{
+			{
+				Name:   "queue",
+				Values: []*monitor.CustomValue{{Key: "active", Value: 12}, {Key: "deferred", Value: 3}},
+				State:  monitor.CollectionState_COLLECTION_STATE_OK,
+			},
+			{
+				Name:     "backups",
+				State:    monitor.CollectionState_COLLECTION_STATE_ERROR,
+				Message:  "exit status 2",
+				ExitCode: 2,
+				Stderr:   "no such job",
+			},
+		},
+		Statuses: []*monitor.CollectionStatus{
+			{
+				Category: monitor.MetricCategory_METRIC_CATEGORY_PROTOCOL_TRAFFIC,
+				State:    monitor.CollectionState_COLLECTION_STATE_OK,
+			},
+			{
+				Category: monitor.MetricCategory_METRIC_CATEGORY_TOP_TALKERS,
+				State:    monitor.CollectionState_COLLECTION_STATE_UNAVAILABLE,
+				Message:  "operation not permitted",
+			},
+		},
+	}
+
+	require.True(t, proto.Equal(expected, snapshot), "got %v", snapshot)
+}
+
+func TestToSnapshot_Empty(t *testing.T) {
+	require.True(t, proto.Equal(&monitor.SystemSnapshot{}, ToSnapshot(nil)))
+	require.True(t, proto.Equal(&monitor.SystemSnapshot{}, ToSnapshot(&entity.SystemMetrics{})))
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	metrics := fullMetrics()
+
+	require.Equal(t, metrics, FromSnapshot(ToSnapshot(metrics)))
+	require.Equal(t, &entity.SystemMetrics{}, FromSnapshot(nil))
+}
+
+func TestFromCategory(t *testing.T) {
+	seen := make(map[entity.Category]bool)
+	for value := range monitor.MetricCategory_name {
+		category, ok := FromCategory(monitor.MetricCategory(value))
+		if value == int32(monitor.MetricCategory_METRIC_CATEGORY_UNSPECIFIED) {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok, monitor.MetricCategory(value).String())
+		require.False(t, seen[category], "%s is mapped twice", category)
+		require.Equal(t, monitor.MetricCategory(value), ToCategory(category))
+		seen[category] = true
+	}
+
+	_, ok := FromCategory(monitor.MetricCategory(100))
+	require.False(t, ok)
+}
@@ -34,3 +34,29 @@ func UnaryLoggerInterceptor(log i.Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+func StreamLoggerInterceptor(log i.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		log.Infof("gRPC stream opened: %s", info.FullMethod)
+
+		err := handler(srv, ss)
+
+		duration := time.Since(start).Milliseconds()
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+
+		log.Infof("gRPC stream closed: method=%s duration=%dms status=%s error=%v",
+			info.FullMethod, duration, status, err)
+
+		return err
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/dimryb/system-monitor/internal/buffer"
 	i "github.com/dimryb/system-monitor/internal/interface"
 	"github.com/dimryb/system-monitor/internal/server/grpc/interceptors"
 	"github.com/dimryb/system-monitor/proto/monitor"
@@ -13,20 +14,22 @@ import (
 )
 
 type Server struct {
-	app i.Application
-	cfg ServerConfig
-	log i.Logger
+	app       i.Application
+	collector *buffer.GlobalCollector
+	cfg       ServerConfig
+	log       i.Logger
 }
 
 type ServerConfig struct {
 	Port string
 }
 
-func NewServer(app i.Application, cfg ServerConfig, log i.Logger) *Server {
+func NewServer(app i.Application, collector *buffer.GlobalCollector, cfg ServerConfig, log i.Logger) *Server {
 	return &Server{
-		app: app,
-		cfg: cfg,
-		log: log,
+		app:       app,
+		collector: collector,
+		cfg:       cfg,
+		log:       log,
 	}
 }
 
@@ -38,9 +41,10 @@ func (s *Server) Run(ctx context.Context) error {
 
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(interceptors.UnaryLoggerInterceptor(s.log)),
+		grpc.StreamInterceptor(interceptors.StreamLoggerInterceptor(s.log)),
 	)
 
-	monitor.RegisterSystemMonitorServer(grpcServer, NewMonitorService(s.app))
+	monitor.RegisterSystemMonitorServer(grpcServer, NewMonitorService(ctx, s.app, s.collector, s.log))
 	reflection.Register(grpcServer)
 
 	s.log.Info("Starting gRPC server", "port", s.cfg.Port)
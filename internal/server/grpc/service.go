@@ -1,17 +1,138 @@
 package grpc
 
 import (
+	"context"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/buffer"
+	"github.com/dimryb/system-monitor/internal/entity"
 	i "github.com/dimryb/system-monitor/internal/interface"
+	"github.com/dimryb/system-monitor/internal/mapper"
 	"github.com/dimryb/system-monitor/proto/monitor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MonitorService struct {
 	monitor.UnimplementedSystemMonitorServer
-	app i.Application
+	ctx       context.Context
+	app       i.Application
+	collector *buffer.GlobalCollector
+	log       i.Logger
+	// second is the unit of the subscription intervals, shortened by tests.
+	second time.Duration
 }
 
-func NewMonitorService(app i.Application) *MonitorService {
+func NewMonitorService(
+	ctx context.Context,
+	app i.Application,
+	collector *buffer.GlobalCollector,
+	log i.Logger,
+) *MonitorService {
 	return &MonitorService{
-		app: app,
+		ctx:       ctx,
+		app:       app,
+		collector: collector,
+		log:       log,
+		second:    time.Second,
+	}
+}
+
+func (s *MonitorService) Subscribe(
+	req *monitor.SubscriptionRequest,
+	stream monitor.SystemMonitor_SubscribeServer,
+) error {
+	if err := validateSubscription(req); err != nil {
+		return err
+	}
+	categories, err := s.categories(req)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	interval := time.Duration(req.GetIntervalSeconds()) * s.second
+	window := time.Duration(req.GetWindowSeconds()) * s.second
+
+	buf := s.collector.Register(ctx, int(req.GetWindowSeconds()), categories)
+	defer s.collector.Unregister(buf.ID())
+	s.log.Debugf("Subscriber %d: interval=%s window=%s", buf.ID(), interval, window)
+
+	warmUp := time.NewTimer(window)
+	defer warmUp.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-s.ctx.Done():
+		return status.Error(codes.Unavailable, "server is shutting down")
+	case <-warmUp.C:
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		metrics := buf.Aggregate().Avg
+		if len(categories) > 0 {
+			metrics = entity.Select(metrics, categories)
+		} else {
+			metrics = withUnavailable(metrics, s.collector.Unavailable())
+		}
+		if err := stream.Send(mapper.ToSnapshot(metrics)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.ctx.Done():
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// withUnavailable adds the categories the host cannot collect to a snapshot of
+// every category, so clients can tell them from the ones without data yet.
+func withUnavailable(m *entity.SystemMetrics, unavailable []entity.CollectionStatus) *entity.SystemMetrics {
+	if len(unavailable) == 0 {
+		return m
+	}
+	if m == nil {
+		m = &entity.SystemMetrics{}
+	}
+	m.Statuses = append(m.Statuses, unavailable...)
+	return m
+}
+
+func validateSubscription(req *monitor.SubscriptionRequest) error {
+	switch {
+	case req.GetIntervalSeconds() == 0:
+		return status.Error(codes.InvalidArgument, "interval_seconds must be greater than zero")
+	case req.GetWindowSeconds() == 0:
+		return status.Error(codes.InvalidArgument, "window_seconds must be greater than zero")
+	case req.GetIntervalSeconds() > req.GetWindowSeconds():
+		return status.Errorf(codes.InvalidArgument,
+			"interval_seconds (%d) must not exceed window_seconds (%d)",
+			req.GetIntervalSeconds(), req.GetWindowSeconds())
+	}
+	return nil
+}
+
+// categories resolves the requested categories. A category the host does not
+// collect is rejected before the subscription starts.
+func (s *MonitorService) categories(req *monitor.SubscriptionRequest) ([]entity.Category, error) {
+	categories := make([]entity.Category, 0, len(req.GetCategories()))
+	for _, requested := range req.GetCategories() {
+		category, ok := mapper.FromCategory(requested)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown metric category %s", requested)
+		}
+		if err := s.collector.Available(category); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "metric category %s is unavailable: %v", requested, err)
+		}
+		categories = append(categories, category)
 	}
+	return categories, nil
 }
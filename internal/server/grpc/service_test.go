@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dimryb/system-monitor/internal/buffer"
+	"github.com/dimryb/system-monitor/internal/config"
+	"github.com/dimryb/system-monitor/internal/entity"
+	"github.com/dimryb/system-monitor/proto/monitor"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateSubscription(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval uint32
+		window   uint32
+		code     codes.Code
+	}{
+		{name: "Valid request", interval: 5, window: 15, code: codes.OK},
+		{name: "Interval equals window", interval: 10, window: 10, code: codes.OK},
+		{name: "Zero interval", interval: 0, window: 15, code: codes.InvalidArgument},
+		{name: "Zero window", interval: 5, window: 0, code: codes.InvalidArgument},
+		{name: "Interval exceeds window", interval: 20, window: 15, code: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSubscription(&monitor.SubscriptionRequest{
+				IntervalSeconds: tt.interval,
+				WindowSeconds:   tt.window,
+			})
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)  {}
+func (nopLogger) Info(string, ...any)   {}
+func (nopLogger) Warn(string, ...any)   {}
+func (nopLogger) Error(string, ...any)  {}
+func (nopLogger) Fatal(string, ...any)  {}
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Infof(string, ...any)  {}
+func (nopLogger) Warnf(string, ...any)  {}
+func (nopLogger) Errorf(string, ...any) {}
+func (nopLogger) Fatalf(string, ...any) {}
+
+func TestSubscriptionCategories(t *testing.T) {
+	disabled := false
+	collector, err := buffer.NewGlobalCollector(context.Background(), nopLogger{}, config.Collectors{
+		CPU: config.CPUCollector{Category: config.Category{Enabled: &disabled}},
+	})
+	require.NoError(t, err)
+	defer collector.Stop()
+	s := NewMonitorService(context.Background(), nil, collector, nopLogger{})
+
+	tests := []struct {
+		name       string
+		categories []monitor.MetricCategory
+		expected   []entity.Category
+		code       codes.Code
+	}{
+		{name: "All categories", code: codes.OK, expected: []entity.Category{}},
+		{
+			name:       "Selected categories",
+			categories: []monitor.MetricCategory{monitor.MetricCategory_METRIC_CATEGORY_LOAD_AVERAGE},
+			expected:   []entity.Category{entity.CategoryLoadAverage},
+			code:       codes.OK,
+		},
+		{
+			name:       "Unspecified category",
+			categories: []monitor.MetricCategory{monitor.MetricCategory_METRIC_CATEGORY_UNSPECIFIED},
+			code:       codes.InvalidArgument,
+		},
+		{
+			name:       "Disabled category",
+			categories: []monitor.MetricCategory{monitor.MetricCategory_METRIC_CATEGORY_CPU},
+			code:       codes.FailedPrecondition,
+		},
+		{
+			name:       "Category without capture",
+			categories: []monitor.MetricCategory{monitor.MetricCategory_METRIC_CATEGORY_TOP_TALKERS},
+			code:       codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, err := s.categories(&monitor.SubscriptionRequest{Categories: tt.categories})
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				require.Equal(t, tt.expected, categories)
+			}
+		})
+	}
+}
+
+// fakeStream records the snapshots sent to a subscriber.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *monitor.SystemSnapshot
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeStream) Send(snapshot *monitor.SystemSnapshot) error {
+	s.sent <- snapshot
+	return nil
+}
+
+func TestSubscribe_Stream(t *testing.T) {
+	collector, err := buffer.NewGlobalCollector(context.Background(), nopLogger{}, config.Collectors{})
+	require.NoError(t, err)
+	defer collector.Stop()
+
+	// subscribe streams the load average every 2 units of 10 ms over a
+	// window of 5, from a server that shuts down when serverCtx is done.
+	subscribe := func(serverCtx, ctx context.Context) (*fakeStream, chan error) {
+		s := NewMonitorService(serverCtx, nil, collector, nopLogger{})
+		s.second = 10 * time.Millisecond
+		stream := &fakeStream{ctx: ctx, sent: make(chan *monitor.SystemSnapshot, 100)}
+		done := make(chan error, 1)
+		go func() {
+			done <- s.Subscribe(&monitor.SubscriptionRequest{
+				IntervalSeconds: 2,
+				WindowSeconds:   5,
+				Categories:      []monitor.MetricCategory{monitor.MetricCategory_METRIC_CATEGORY_LOAD_AVERAGE},
+			}, stream)
+		}()
+		return stream, done
+	}
+	receive := func(stream *fakeStream) *monitor.SystemSnapshot {
+		select {
+		case snapshot := <-stream.sent:
+			return snapshot
+		case <-time.After(time.Second):
+			t.Fatal("no snapshot sent")
+			return nil
+		}
+	}
+	ended := func(done chan error) error {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(time.Second):
+			t.Fatal("stream not ended")
+			return nil
+		}
+	}
+
+	// Nothing is sent before the window is filled, then one snapshot every
+	// interval until the client cancels.
+	ctx, cancel := context.WithCancel(context.Background())
+	started := time.Now()
+	stream, done := subscribe(context.Background(), ctx)
+	first := receive(stream)
+	require.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	require.NotNil(t, first.GetLoadAvg())
+	for range 3 {
+		receive(stream)
+	}
+	require.GreaterOrEqual(t, time.Since(started), 110*time.Millisecond)
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(ended(done)))
+
+	// The server shutting down ends the stream, during the warm-up too.
+	for _, warmedUp := range []bool{false, true} {
+		serverCtx, shutdown := context.WithCancel(context.Background())
+		stream, done := subscribe(serverCtx, context.Background())
+		if warmedUp {
+			receive(stream)
+		}
+		shutdown()
+		require.Equal(t, codes.Unavailable, status.Code(ended(done)), "warmed up: %v", warmedUp)
+		require.Empty(t, stream.sent)
+	}
+}
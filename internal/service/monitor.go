@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/dimryb/system-monitor/internal/buffer"
 	"github.com/dimryb/system-monitor/internal/config"
@@ -19,13 +18,23 @@ type Monitor struct {
 	globalCollector *buffer.GlobalCollector
 }
 
-func NewMonitorService(ctx context.Context, app i.Application, logger i.Logger, cfg *config.MonitorConfig) *Monitor {
+func NewMonitorService(
+	ctx context.Context,
+	app i.Application,
+	logger i.Logger,
+	cfg *config.MonitorConfig,
+) (*Monitor, error) {
+	globalCollector, err := buffer.NewGlobalCollector(ctx, logger, cfg.Collectors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collector: %w", err)
+	}
+
 	return &Monitor{
 		app:             app,
 		log:             logger,
 		cfg:             cfg,
-		globalCollector: buffer.NewGlobalCollector(ctx, logger),
-	}
+		globalCollector: globalCollector,
+	}, nil
 }
 
 func (m *Monitor) Run(ctx context.Context) error {
@@ -37,49 +46,23 @@ func (m *Monitor) Run(ctx context.Context) error {
 		m.log.Debugf("gRPC server starting..")
 		grpcServer := grpc.NewServer(
 			m.app,
+			m.globalCollector,
 			grpc.ServerConfig{
 				Port: m.cfg.GRPC.Port,
 			},
 			m.log,
 		)
 		if err := grpcServer.Run(ctx); err != nil {
-			m.log.Fatalf("Failed to start gRPC server: %m", err.Error())
+			m.log.Fatalf("Failed to start gRPC server: %v", err)
 		}
 	}()
 
-	globalCollector := buffer.NewGlobalCollector(ctx, m.log)
-	buf := globalCollector.Register(15)
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		globalCollector.Start()
-	}()
-
-	// Stub register collector
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-
-		ticker := time.NewTicker(time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				m.log.Infof("Stub collector stopped.")
-				return
-			case <-ticker.C:
-				data := buf.Get()
-				m.log.Debugf("Buffer size: %d", len(data))
-				fmt.Println("Data:")
-				for _, v := range data {
-					fmt.Println(*v)
-				}
-			}
-		}
+		m.globalCollector.Start()
+		m.log.Infof("Global collector stopped.")
 	}()
-	// end Stub
 
 	m.log.Infof("System monitor is running...")
 
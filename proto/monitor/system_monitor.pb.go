@@ -21,12 +21,197 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MetricCategory int32
+
+const (
+	MetricCategory_METRIC_CATEGORY_UNSPECIFIED      MetricCategory = 0
+	MetricCategory_METRIC_CATEGORY_LOAD_AVERAGE     MetricCategory = 1
+	MetricCategory_METRIC_CATEGORY_CPU              MetricCategory = 2
+	MetricCategory_METRIC_CATEGORY_DISK             MetricCategory = 3
+	MetricCategory_METRIC_CATEGORY_FILESYSTEM       MetricCategory = 4
+	MetricCategory_METRIC_CATEGORY_CONNECTIONS      MetricCategory = 5
+	MetricCategory_METRIC_CATEGORY_TCP_STATES       MetricCategory = 6
+	MetricCategory_METRIC_CATEGORY_PROTOCOL_TRAFFIC MetricCategory = 7
+	MetricCategory_METRIC_CATEGORY_TOP_TALKERS      MetricCategory = 8
+	MetricCategory_METRIC_CATEGORY_CUSTOM           MetricCategory = 9
+	MetricCategory_METRIC_CATEGORY_MEMORY           MetricCategory = 10
+	MetricCategory_METRIC_CATEGORY_INTERFACES       MetricCategory = 11
+	MetricCategory_METRIC_CATEGORY_PRESSURE         MetricCategory = 12
+	MetricCategory_METRIC_CATEGORY_CGROUPS          MetricCategory = 13
+)
+
+// Enum value maps for MetricCategory.
+var (
+	MetricCategory_name = map[int32]string{
+		0:  "METRIC_CATEGORY_UNSPECIFIED",
+		1:  "METRIC_CATEGORY_LOAD_AVERAGE",
+		2:  "METRIC_CATEGORY_CPU",
+		3:  "METRIC_CATEGORY_DISK",
+		4:  "METRIC_CATEGORY_FILESYSTEM",
+		5:  "METRIC_CATEGORY_CONNECTIONS",
+		6:  "METRIC_CATEGORY_TCP_STATES",
+		7:  "METRIC_CATEGORY_PROTOCOL_TRAFFIC",
+		8:  "METRIC_CATEGORY_TOP_TALKERS",
+		9:  "METRIC_CATEGORY_CUSTOM",
+		10: "METRIC_CATEGORY_MEMORY",
+		11: "METRIC_CATEGORY_INTERFACES",
+		12: "METRIC_CATEGORY_PRESSURE",
+		13: "METRIC_CATEGORY_CGROUPS",
+	}
+	MetricCategory_value = map[string]int32{
+		"METRIC_CATEGORY_UNSPECIFIED":      0,
+		"METRIC_CATEGORY_LOAD_AVERAGE":     1,
+		"METRIC_CATEGORY_CPU":              2,
+		"METRIC_CATEGORY_DISK":             3,
+		"METRIC_CATEGORY_FILESYSTEM":       4,
+		"METRIC_CATEGORY_CONNECTIONS":      5,
+		"METRIC_CATEGORY_TCP_STATES":       6,
+		"METRIC_CATEGORY_PROTOCOL_TRAFFIC": 7,
+		"METRIC_CATEGORY_TOP_TALKERS":      8,
+		"METRIC_CATEGORY_CUSTOM":           9,
+		"METRIC_CATEGORY_MEMORY":           10,
+		"METRIC_CATEGORY_INTERFACES":       11,
+		"METRIC_CATEGORY_PRESSURE":         12,
+		"METRIC_CATEGORY_CGROUPS":          13,
+	}
+)
+
+func (x MetricCategory) Enum() *MetricCategory {
+	p := new(MetricCategory)
+	*p = x
+	return p
+}
+
+func (x MetricCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MetricCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_system_monitor_proto_enumTypes[0].Descriptor()
+}
+
+func (MetricCategory) Type() protoreflect.EnumType {
+	return &file_monitor_system_monitor_proto_enumTypes[0]
+}
+
+func (x MetricCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MetricCategory.Descriptor instead.
+func (MetricCategory) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{0}
+}
+
+type TrafficSource int32
+
+const (
+	TrafficSource_TRAFFIC_SOURCE_UNSPECIFIED TrafficSource = 0
+	TrafficSource_TRAFFIC_SOURCE_CAPTURE     TrafficSource = 1 // decoded packets
+	TrafficSource_TRAFFIC_SOURCE_PROCFS      TrafficSource = 2 // estimated from /proc/net/snmp and /proc/net/dev counters
+)
+
+// Enum value maps for TrafficSource.
+var (
+	TrafficSource_name = map[int32]string{
+		0: "TRAFFIC_SOURCE_UNSPECIFIED",
+		1: "TRAFFIC_SOURCE_CAPTURE",
+		2: "TRAFFIC_SOURCE_PROCFS",
+	}
+	TrafficSource_value = map[string]int32{
+		"TRAFFIC_SOURCE_UNSPECIFIED": 0,
+		"TRAFFIC_SOURCE_CAPTURE":     1,
+		"TRAFFIC_SOURCE_PROCFS":      2,
+	}
+)
+
+func (x TrafficSource) Enum() *TrafficSource {
+	p := new(TrafficSource)
+	*p = x
+	return p
+}
+
+func (x TrafficSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TrafficSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_system_monitor_proto_enumTypes[1].Descriptor()
+}
+
+func (TrafficSource) Type() protoreflect.EnumType {
+	return &file_monitor_system_monitor_proto_enumTypes[1]
+}
+
+func (x TrafficSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TrafficSource.Descriptor instead.
+func (TrafficSource) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{1}
+}
+
+type CollectionState int32
+
+const (
+	CollectionState_COLLECTION_STATE_UNSPECIFIED CollectionState = 0
+	CollectionState_COLLECTION_STATE_OK          CollectionState = 1
+	CollectionState_COLLECTION_STATE_UNAVAILABLE CollectionState = 2 // not measurable on this host, e.g. capture without CAP_NET_RAW
+	CollectionState_COLLECTION_STATE_ERROR       CollectionState = 3 // the latest collection failed
+)
+
+// Enum value maps for CollectionState.
+var (
+	CollectionState_name = map[int32]string{
+		0: "COLLECTION_STATE_UNSPECIFIED",
+		1: "COLLECTION_STATE_OK",
+		2: "COLLECTION_STATE_UNAVAILABLE",
+		3: "COLLECTION_STATE_ERROR",
+	}
+	CollectionState_value = map[string]int32{
+		"COLLECTION_STATE_UNSPECIFIED": 0,
+		"COLLECTION_STATE_OK":          1,
+		"COLLECTION_STATE_UNAVAILABLE": 2,
+		"COLLECTION_STATE_ERROR":       3,
+	}
+)
+
+func (x CollectionState) Enum() *CollectionState {
+	p := new(CollectionState)
+	*p = x
+	return p
+}
+
+func (x CollectionState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CollectionState) Descriptor() protoreflect.EnumDescriptor {
+	return file_monitor_system_monitor_proto_enumTypes[2].Descriptor()
+}
+
+func (CollectionState) Type() protoreflect.EnumType {
+	return &file_monitor_system_monitor_proto_enumTypes[2]
+}
+
+func (x CollectionState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CollectionState.Descriptor instead.
+func (CollectionState) EnumDescriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{2}
+}
+
 type SubscriptionRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	IntervalSeconds uint32                 `protobuf:"varint,1,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // N
 	WindowSeconds   uint32                 `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`       // M
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Sections to send. Empty means every category the host provides.
+	Categories    []MetricCategory `protobuf:"varint,3,rep,packed,name=categories,proto3,enum=systemmonitor.MetricCategory" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubscriptionRequest) Reset() {
@@ -73,17 +258,32 @@ func (x *SubscriptionRequest) GetWindowSeconds() uint32 {
 	return 0
 }
 
+func (x *SubscriptionRequest) GetCategories() []MetricCategory {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
 type SystemSnapshot struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	LoadAvg         *LoadAverage           `protobuf:"bytes,1,opt,name=load_avg,json=loadAvg,proto3" json:"load_avg,omitempty"`
-	CpuUsage        *CpuUsage              `protobuf:"bytes,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
-	DiskStats       []*DiskStats           `protobuf:"bytes,3,rep,name=disk_stats,json=diskStats,proto3" json:"disk_stats,omitempty"`
-	FsUsage         []*FsUsage             `protobuf:"bytes,4,rep,name=fs_usage,json=fsUsage,proto3" json:"fs_usage,omitempty"`
-	ProtocolTraffic []*ProtocolTraffic     `protobuf:"bytes,5,rep,name=protocol_traffic,json=protocolTraffic,proto3" json:"protocol_traffic,omitempty"`
-	Connections     []*NetworkConnection   `protobuf:"bytes,6,rep,name=connections,proto3" json:"connections,omitempty"`
-	TopTalkers      []*TopTalker           `protobuf:"bytes,7,rep,name=top_talkers,json=topTalkers,proto3" json:"top_talkers,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	LoadAvg               *LoadAverage           `protobuf:"bytes,1,opt,name=load_avg,json=loadAvg,proto3" json:"load_avg,omitempty"`
+	CpuUsage              *CpuUsage              `protobuf:"bytes,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	DiskStats             []*DiskStats           `protobuf:"bytes,3,rep,name=disk_stats,json=diskStats,proto3" json:"disk_stats,omitempty"`
+	FsUsage               []*FsUsage             `protobuf:"bytes,4,rep,name=fs_usage,json=fsUsage,proto3" json:"fs_usage,omitempty"`
+	ProtocolTraffic       []*ProtocolTraffic     `protobuf:"bytes,5,rep,name=protocol_traffic,json=protocolTraffic,proto3" json:"protocol_traffic,omitempty"`
+	Connections           []*NetworkConnection   `protobuf:"bytes,6,rep,name=connections,proto3" json:"connections,omitempty"`
+	TopTalkers            []*TopTalker           `protobuf:"bytes,7,rep,name=top_talkers,json=topTalkers,proto3" json:"top_talkers,omitempty"`
+	TcpStates             []*TcpStateCount       `protobuf:"bytes,8,rep,name=tcp_states,json=tcpStates,proto3" json:"tcp_states,omitempty"`
+	ProtocolTrafficSource TrafficSource          `protobuf:"varint,9,opt,name=protocol_traffic_source,json=protocolTrafficSource,proto3,enum=systemmonitor.TrafficSource" json:"protocol_traffic_source,omitempty"`
+	Statuses              []*CollectionStatus    `protobuf:"bytes,10,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	CustomMetrics         []*CustomMetric        `protobuf:"bytes,11,rep,name=custom_metrics,json=customMetrics,proto3" json:"custom_metrics,omitempty"`
+	Memory                *MemoryStats           `protobuf:"bytes,12,opt,name=memory,proto3" json:"memory,omitempty"`
+	Interfaces            []*InterfaceStats      `protobuf:"bytes,13,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Pressure              []*PressureStats       `protobuf:"bytes,14,rep,name=pressure,proto3" json:"pressure,omitempty"`
+	Cgroups               []*CgroupStats         `protobuf:"bytes,15,rep,name=cgroups,proto3" json:"cgroups,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *SystemSnapshot) Reset() {
@@ -165,18 +365,138 @@ func (x *SystemSnapshot) GetTopTalkers() []*TopTalker {
 	return nil
 }
 
+func (x *SystemSnapshot) GetTcpStates() []*TcpStateCount {
+	if x != nil {
+		return x.TcpStates
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetProtocolTrafficSource() TrafficSource {
+	if x != nil {
+		return x.ProtocolTrafficSource
+	}
+	return TrafficSource_TRAFFIC_SOURCE_UNSPECIFIED
+}
+
+func (x *SystemSnapshot) GetStatuses() []*CollectionStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetCustomMetrics() []*CustomMetric {
+	if x != nil {
+		return x.CustomMetrics
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetMemory() *MemoryStats {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetInterfaces() []*InterfaceStats {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetPressure() []*PressureStats {
+	if x != nil {
+		return x.Pressure
+	}
+	return nil
+}
+
+func (x *SystemSnapshot) GetCgroups() []*CgroupStats {
+	if x != nil {
+		return x.Cgroups
+	}
+	return nil
+}
+
+// CollectionStatus tells whether a category could be measured, so an empty
+// section can be told from one that is not measurable.
+type CollectionStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      MetricCategory         `protobuf:"varint,1,opt,name=category,proto3,enum=systemmonitor.MetricCategory" json:"category,omitempty"`
+	State         CollectionState        `protobuf:"varint,2,opt,name=state,proto3,enum=systemmonitor.CollectionState" json:"state,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionStatus) Reset() {
+	*x = CollectionStatus{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionStatus) ProtoMessage() {}
+
+func (x *CollectionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionStatus.ProtoReflect.Descriptor instead.
+func (*CollectionStatus) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CollectionStatus) GetCategory() MetricCategory {
+	if x != nil {
+		return x.Category
+	}
+	return MetricCategory_METRIC_CATEGORY_UNSPECIFIED
+}
+
+func (x *CollectionStatus) GetState() CollectionState {
+	if x != nil {
+		return x.State
+	}
+	return CollectionState_COLLECTION_STATE_UNSPECIFIED
+}
+
+func (x *CollectionStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 type LoadAverage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OneMin        float64                `protobuf:"fixed64,1,opt,name=one_min,json=oneMin,proto3" json:"one_min,omitempty"`
 	FiveMins      float64                `protobuf:"fixed64,2,opt,name=five_mins,json=fiveMins,proto3" json:"five_mins,omitempty"`
 	FifteenMins   float64                `protobuf:"fixed64,3,opt,name=fifteen_mins,json=fifteenMins,proto3" json:"fifteen_mins,omitempty"`
+	RunningTasks  uint32                 `protobuf:"varint,4,opt,name=running_tasks,json=runningTasks,proto3" json:"running_tasks,omitempty"`
+	TotalTasks    uint32                 `protobuf:"varint,5,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoadAverage) Reset() {
 	*x = LoadAverage{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[2]
+	mi := &file_monitor_system_monitor_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -188,7 +508,7 @@ func (x *LoadAverage) String() string {
 func (*LoadAverage) ProtoMessage() {}
 
 func (x *LoadAverage) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[2]
+	mi := &file_monitor_system_monitor_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -201,7 +521,7 @@ func (x *LoadAverage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadAverage.ProtoReflect.Descriptor instead.
 func (*LoadAverage) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{2}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *LoadAverage) GetOneMin() float64 {
@@ -225,18 +545,38 @@ func (x *LoadAverage) GetFifteenMins() float64 {
 	return 0
 }
 
+func (x *LoadAverage) GetRunningTasks() uint32 {
+	if x != nil {
+		return x.RunningTasks
+	}
+	return 0
+}
+
+func (x *LoadAverage) GetTotalTasks() uint32 {
+	if x != nil {
+		return x.TotalTasks
+	}
+	return 0
+}
+
 type CpuUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          float64                `protobuf:"fixed64,1,opt,name=user,proto3" json:"user,omitempty"`
 	System        float64                `protobuf:"fixed64,2,opt,name=system,proto3" json:"system,omitempty"`
 	Idle          float64                `protobuf:"fixed64,3,opt,name=idle,proto3" json:"idle,omitempty"`
+	Nice          float64                `protobuf:"fixed64,4,opt,name=nice,proto3" json:"nice,omitempty"`
+	Iowait        float64                `protobuf:"fixed64,5,opt,name=iowait,proto3" json:"iowait,omitempty"`
+	Irq           float64                `protobuf:"fixed64,6,opt,name=irq,proto3" json:"irq,omitempty"`
+	Softirq       float64                `protobuf:"fixed64,7,opt,name=softirq,proto3" json:"softirq,omitempty"`
+	Steal         float64                `protobuf:"fixed64,8,opt,name=steal,proto3" json:"steal,omitempty"`
+	Cores         []*CpuCoreUsage        `protobuf:"bytes,9,rep,name=cores,proto3" json:"cores,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CpuUsage) Reset() {
 	*x = CpuUsage{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[3]
+	mi := &file_monitor_system_monitor_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -245,42 +585,536 @@ func (x *CpuUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CpuUsage) ProtoMessage() {}
+func (*CpuUsage) ProtoMessage() {}
+
+func (x *CpuUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CpuUsage.ProtoReflect.Descriptor instead.
+func (*CpuUsage) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CpuUsage) GetUser() float64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetSystem() float64 {
+	if x != nil {
+		return x.System
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetIdle() float64 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetNice() float64 {
+	if x != nil {
+		return x.Nice
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetIowait() float64 {
+	if x != nil {
+		return x.Iowait
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetIrq() float64 {
+	if x != nil {
+		return x.Irq
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetSoftirq() float64 {
+	if x != nil {
+		return x.Softirq
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetSteal() float64 {
+	if x != nil {
+		return x.Steal
+	}
+	return 0
+}
+
+func (x *CpuUsage) GetCores() []*CpuCoreUsage {
+	if x != nil {
+		return x.Cores
+	}
+	return nil
+}
+
+type CpuCoreUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Core          string                 `protobuf:"bytes,1,opt,name=core,proto3" json:"core,omitempty"`
+	User          float64                `protobuf:"fixed64,2,opt,name=user,proto3" json:"user,omitempty"`
+	System        float64                `protobuf:"fixed64,3,opt,name=system,proto3" json:"system,omitempty"`
+	Idle          float64                `protobuf:"fixed64,4,opt,name=idle,proto3" json:"idle,omitempty"`
+	Nice          float64                `protobuf:"fixed64,5,opt,name=nice,proto3" json:"nice,omitempty"`
+	Iowait        float64                `protobuf:"fixed64,6,opt,name=iowait,proto3" json:"iowait,omitempty"`
+	Irq           float64                `protobuf:"fixed64,7,opt,name=irq,proto3" json:"irq,omitempty"`
+	Softirq       float64                `protobuf:"fixed64,8,opt,name=softirq,proto3" json:"softirq,omitempty"`
+	Steal         float64                `protobuf:"fixed64,9,opt,name=steal,proto3" json:"steal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CpuCoreUsage) Reset() {
+	*x = CpuCoreUsage{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CpuCoreUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CpuCoreUsage) ProtoMessage() {}
+
+func (x *CpuCoreUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CpuCoreUsage.ProtoReflect.Descriptor instead.
+func (*CpuCoreUsage) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CpuCoreUsage) GetCore() string {
+	if x != nil {
+		return x.Core
+	}
+	return ""
+}
+
+func (x *CpuCoreUsage) GetUser() float64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetSystem() float64 {
+	if x != nil {
+		return x.System
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetIdle() float64 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetNice() float64 {
+	if x != nil {
+		return x.Nice
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetIowait() float64 {
+	if x != nil {
+		return x.Iowait
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetIrq() float64 {
+	if x != nil {
+		return x.Irq
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetSoftirq() float64 {
+	if x != nil {
+		return x.Softirq
+	}
+	return 0
+}
+
+func (x *CpuCoreUsage) GetSteal() float64 {
+	if x != nil {
+		return x.Steal
+	}
+	return 0
+}
+
+// MemoryStats counts used memory as free(1) does: total minus available.
+type MemoryStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TotalMb         uint64                 `protobuf:"varint,1,opt,name=total_mb,json=totalMb,proto3" json:"total_mb,omitempty"`
+	AvailableMb     uint64                 `protobuf:"varint,2,opt,name=available_mb,json=availableMb,proto3" json:"available_mb,omitempty"`
+	UsedMb          uint64                 `protobuf:"varint,3,opt,name=used_mb,json=usedMb,proto3" json:"used_mb,omitempty"`
+	BuffCacheMb     uint64                 `protobuf:"varint,4,opt,name=buff_cache_mb,json=buffCacheMb,proto3" json:"buff_cache_mb,omitempty"`
+	DirtyMb         uint64                 `protobuf:"varint,5,opt,name=dirty_mb,json=dirtyMb,proto3" json:"dirty_mb,omitempty"`
+	SwapTotalMb     uint64                 `protobuf:"varint,6,opt,name=swap_total_mb,json=swapTotalMb,proto3" json:"swap_total_mb,omitempty"`
+	SwapUsedMb      uint64                 `protobuf:"varint,7,opt,name=swap_used_mb,json=swapUsedMb,proto3" json:"swap_used_mb,omitempty"`
+	SwapInKbPerSec  float64                `protobuf:"fixed64,8,opt,name=swap_in_kb_per_sec,json=swapInKbPerSec,proto3" json:"swap_in_kb_per_sec,omitempty"`
+	SwapOutKbPerSec float64                `protobuf:"fixed64,9,opt,name=swap_out_kb_per_sec,json=swapOutKbPerSec,proto3" json:"swap_out_kb_per_sec,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MemoryStats) Reset() {
+	*x = MemoryStats{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemoryStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryStats) ProtoMessage() {}
+
+func (x *MemoryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryStats.ProtoReflect.Descriptor instead.
+func (*MemoryStats) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MemoryStats) GetTotalMb() uint64 {
+	if x != nil {
+		return x.TotalMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetAvailableMb() uint64 {
+	if x != nil {
+		return x.AvailableMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetUsedMb() uint64 {
+	if x != nil {
+		return x.UsedMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetBuffCacheMb() uint64 {
+	if x != nil {
+		return x.BuffCacheMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetDirtyMb() uint64 {
+	if x != nil {
+		return x.DirtyMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetSwapTotalMb() uint64 {
+	if x != nil {
+		return x.SwapTotalMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetSwapUsedMb() uint64 {
+	if x != nil {
+		return x.SwapUsedMb
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetSwapInKbPerSec() float64 {
+	if x != nil {
+		return x.SwapInKbPerSec
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetSwapOutKbPerSec() float64 {
+	if x != nil {
+		return x.SwapOutKbPerSec
+	}
+	return 0
+}
+
+// PressureStats holds the pressure stall information of a resource from
+// /proc/pressure.
+type PressureStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resource      string                 `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"` // cpu, memory or io
+	Some          *PressureLine          `protobuf:"bytes,2,opt,name=some,proto3" json:"some,omitempty"`
+	Full          *PressureLine          `protobuf:"bytes,3,opt,name=full,proto3" json:"full,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PressureStats) Reset() {
+	*x = PressureStats{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PressureStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PressureStats) ProtoMessage() {}
+
+func (x *PressureStats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PressureStats.ProtoReflect.Descriptor instead.
+func (*PressureStats) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PressureStats) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *PressureStats) GetSome() *PressureLine {
+	if x != nil {
+		return x.Some
+	}
+	return nil
+}
+
+func (x *PressureStats) GetFull() *PressureLine {
+	if x != nil {
+		return x.Full
+	}
+	return nil
+}
+
+// PressureLine is the share of time, in percent, tasks were stalled. percent
+// is measured over the window from the stall time counter, avg10, avg60 and
+// avg300 are the running averages of the kernel.
+type PressureLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Percent       float64                `protobuf:"fixed64,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	Avg10         float64                `protobuf:"fixed64,2,opt,name=avg10,proto3" json:"avg10,omitempty"`
+	Avg60         float64                `protobuf:"fixed64,3,opt,name=avg60,proto3" json:"avg60,omitempty"`
+	Avg300        float64                `protobuf:"fixed64,4,opt,name=avg300,proto3" json:"avg300,omitempty"`
+	TotalUsec     uint64                 `protobuf:"varint,5,opt,name=total_usec,json=totalUsec,proto3" json:"total_usec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PressureLine) Reset() {
+	*x = PressureLine{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PressureLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PressureLine) ProtoMessage() {}
+
+func (x *PressureLine) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PressureLine.ProtoReflect.Descriptor instead.
+func (*PressureLine) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PressureLine) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *PressureLine) GetAvg10() float64 {
+	if x != nil {
+		return x.Avg10
+	}
+	return 0
+}
+
+func (x *PressureLine) GetAvg60() float64 {
+	if x != nil {
+		return x.Avg60
+	}
+	return 0
+}
+
+func (x *PressureLine) GetAvg300() float64 {
+	if x != nil {
+		return x.Avg300
+	}
+	return 0
+}
+
+func (x *PressureLine) GetTotalUsec() uint64 {
+	if x != nil {
+		return x.TotalUsec
+	}
+	return 0
+}
+
+// CgroupStats holds the resources used by a cgroup v2 (a systemd unit or a
+// container), with cpu_percent 100 for one fully used CPU.
+type CgroupStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Path            string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // relative to the root, as in /proc/<pid>/cgroup
+	CpuPercent      float64                `protobuf:"fixed64,2,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	MemoryMb        uint64                 `protobuf:"varint,3,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	MemoryLimitMb   uint64                 `protobuf:"varint,4,opt,name=memory_limit_mb,json=memoryLimitMb,proto3" json:"memory_limit_mb,omitempty"` // 0 when not limited
+	IoReadKbPerSec  float64                `protobuf:"fixed64,5,opt,name=io_read_kb_per_sec,json=ioReadKbPerSec,proto3" json:"io_read_kb_per_sec,omitempty"`
+	IoWriteKbPerSec float64                `protobuf:"fixed64,6,opt,name=io_write_kb_per_sec,json=ioWriteKbPerSec,proto3" json:"io_write_kb_per_sec,omitempty"`
+	Pids            uint64                 `protobuf:"varint,7,opt,name=pids,proto3" json:"pids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CgroupStats) Reset() {
+	*x = CgroupStats{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CgroupStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CgroupStats) ProtoMessage() {}
+
+func (x *CgroupStats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CgroupStats.ProtoReflect.Descriptor instead.
+func (*CgroupStats) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CgroupStats) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CgroupStats) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
 
-func (x *CpuUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[3]
+func (x *CgroupStats) GetMemoryMb() uint64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.MemoryMb
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use CpuUsage.ProtoReflect.Descriptor instead.
-func (*CpuUsage) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{3}
+func (x *CgroupStats) GetMemoryLimitMb() uint64 {
+	if x != nil {
+		return x.MemoryLimitMb
+	}
+	return 0
 }
 
-func (x *CpuUsage) GetUser() float64 {
+func (x *CgroupStats) GetIoReadKbPerSec() float64 {
 	if x != nil {
-		return x.User
+		return x.IoReadKbPerSec
 	}
 	return 0
 }
 
-func (x *CpuUsage) GetSystem() float64 {
+func (x *CgroupStats) GetIoWriteKbPerSec() float64 {
 	if x != nil {
-		return x.System
+		return x.IoWriteKbPerSec
 	}
 	return 0
 }
 
-func (x *CpuUsage) GetIdle() float64 {
+func (x *CgroupStats) GetPids() uint64 {
 	if x != nil {
-		return x.Idle
+		return x.Pids
 	}
 	return 0
 }
@@ -296,7 +1130,7 @@ type DiskStats struct {
 
 func (x *DiskStats) Reset() {
 	*x = DiskStats{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[4]
+	mi := &file_monitor_system_monitor_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -308,7 +1142,7 @@ func (x *DiskStats) String() string {
 func (*DiskStats) ProtoMessage() {}
 
 func (x *DiskStats) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[4]
+	mi := &file_monitor_system_monitor_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -321,7 +1155,7 @@ func (x *DiskStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiskStats.ProtoReflect.Descriptor instead.
 func (*DiskStats) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{4}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DiskStats) GetDevice() string {
@@ -360,7 +1194,7 @@ type FsUsage struct {
 
 func (x *FsUsage) Reset() {
 	*x = FsUsage{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[5]
+	mi := &file_monitor_system_monitor_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +1206,7 @@ func (x *FsUsage) String() string {
 func (*FsUsage) ProtoMessage() {}
 
 func (x *FsUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[5]
+	mi := &file_monitor_system_monitor_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +1219,7 @@ func (x *FsUsage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FsUsage.ProtoReflect.Descriptor instead.
 func (*FsUsage) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{5}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *FsUsage) GetMountPoint() string {
@@ -437,6 +1271,132 @@ func (x *FsUsage) GetInodesUsedPercent() float64 {
 	return 0
 }
 
+// InterfaceStats holds per second rates from /proc/net/dev and the link from
+// /sys/class/net.
+type InterfaceStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`                           // operstate: up, down, unknown...
+	SpeedMbps       uint64                 `protobuf:"varint,3,opt,name=speed_mbps,json=speedMbps,proto3" json:"speed_mbps,omitempty"` // 0 when unknown
+	RxBytesPerSec   float64                `protobuf:"fixed64,4,opt,name=rx_bytes_per_sec,json=rxBytesPerSec,proto3" json:"rx_bytes_per_sec,omitempty"`
+	TxBytesPerSec   float64                `protobuf:"fixed64,5,opt,name=tx_bytes_per_sec,json=txBytesPerSec,proto3" json:"tx_bytes_per_sec,omitempty"`
+	RxPacketsPerSec float64                `protobuf:"fixed64,6,opt,name=rx_packets_per_sec,json=rxPacketsPerSec,proto3" json:"rx_packets_per_sec,omitempty"`
+	TxPacketsPerSec float64                `protobuf:"fixed64,7,opt,name=tx_packets_per_sec,json=txPacketsPerSec,proto3" json:"tx_packets_per_sec,omitempty"`
+	RxErrorsPerSec  float64                `protobuf:"fixed64,8,opt,name=rx_errors_per_sec,json=rxErrorsPerSec,proto3" json:"rx_errors_per_sec,omitempty"`
+	TxErrorsPerSec  float64                `protobuf:"fixed64,9,opt,name=tx_errors_per_sec,json=txErrorsPerSec,proto3" json:"tx_errors_per_sec,omitempty"`
+	RxDropsPerSec   float64                `protobuf:"fixed64,10,opt,name=rx_drops_per_sec,json=rxDropsPerSec,proto3" json:"rx_drops_per_sec,omitempty"`
+	TxDropsPerSec   float64                `protobuf:"fixed64,11,opt,name=tx_drops_per_sec,json=txDropsPerSec,proto3" json:"tx_drops_per_sec,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InterfaceStats) Reset() {
+	*x = InterfaceStats{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterfaceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterfaceStats) ProtoMessage() {}
+
+func (x *InterfaceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterfaceStats.ProtoReflect.Descriptor instead.
+func (*InterfaceStats) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InterfaceStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InterfaceStats) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *InterfaceStats) GetSpeedMbps() uint64 {
+	if x != nil {
+		return x.SpeedMbps
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxBytesPerSec() float64 {
+	if x != nil {
+		return x.RxBytesPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxBytesPerSec() float64 {
+	if x != nil {
+		return x.TxBytesPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxPacketsPerSec() float64 {
+	if x != nil {
+		return x.RxPacketsPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxPacketsPerSec() float64 {
+	if x != nil {
+		return x.TxPacketsPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxErrorsPerSec() float64 {
+	if x != nil {
+		return x.RxErrorsPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxErrorsPerSec() float64 {
+	if x != nil {
+		return x.TxErrorsPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxDropsPerSec() float64 {
+	if x != nil {
+		return x.RxDropsPerSec
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxDropsPerSec() float64 {
+	if x != nil {
+		return x.TxDropsPerSec
+	}
+	return 0
+}
+
 type ProtocolTraffic struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Protocol      string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
@@ -448,7 +1408,7 @@ type ProtocolTraffic struct {
 
 func (x *ProtocolTraffic) Reset() {
 	*x = ProtocolTraffic{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[6]
+	mi := &file_monitor_system_monitor_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -460,7 +1420,7 @@ func (x *ProtocolTraffic) String() string {
 func (*ProtocolTraffic) ProtoMessage() {}
 
 func (x *ProtocolTraffic) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[6]
+	mi := &file_monitor_system_monitor_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,7 +1433,7 @@ func (x *ProtocolTraffic) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtocolTraffic.ProtoReflect.Descriptor instead.
 func (*ProtocolTraffic) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{6}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ProtocolTraffic) GetProtocol() string {
@@ -510,7 +1470,7 @@ type NetworkConnection struct {
 
 func (x *NetworkConnection) Reset() {
 	*x = NetworkConnection{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[7]
+	mi := &file_monitor_system_monitor_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -522,7 +1482,7 @@ func (x *NetworkConnection) String() string {
 func (*NetworkConnection) ProtoMessage() {}
 
 func (x *NetworkConnection) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[7]
+	mi := &file_monitor_system_monitor_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -535,7 +1495,7 @@ func (x *NetworkConnection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkConnection.ProtoReflect.Descriptor instead.
 func (*NetworkConnection) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{7}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *NetworkConnection) GetCommand() string {
@@ -585,7 +1545,7 @@ type TopTalker struct {
 
 func (x *TopTalker) Reset() {
 	*x = TopTalker{}
-	mi := &file_monitor_system_monitor_proto_msgTypes[8]
+	mi := &file_monitor_system_monitor_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -597,7 +1557,7 @@ func (x *TopTalker) String() string {
 func (*TopTalker) ProtoMessage() {}
 
 func (x *TopTalker) ProtoReflect() protoreflect.Message {
-	mi := &file_monitor_system_monitor_proto_msgTypes[8]
+	mi := &file_monitor_system_monitor_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -610,7 +1570,7 @@ func (x *TopTalker) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TopTalker.ProtoReflect.Descriptor instead.
 func (*TopTalker) Descriptor() ([]byte, []int) {
-	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{8}
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *TopTalker) GetSrc() string {
@@ -641,14 +1601,215 @@ func (x *TopTalker) GetBps() uint64 {
 	return 0
 }
 
+type TcpStateCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Family        string                 `protobuf:"bytes,1,opt,name=family,proto3" json:"family,omitempty"` // ipv4, ipv6
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`   // ESTABLISHED, TIME_WAIT, CLOSE_WAIT...
+	Count         float64                `protobuf:"fixed64,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TcpStateCount) Reset() {
+	*x = TcpStateCount{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TcpStateCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TcpStateCount) ProtoMessage() {}
+
+func (x *TcpStateCount) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TcpStateCount.ProtoReflect.Descriptor instead.
+func (*TcpStateCount) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TcpStateCount) GetFamily() string {
+	if x != nil {
+		return x.Family
+	}
+	return ""
+}
+
+func (x *TcpStateCount) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TcpStateCount) GetCount() float64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// CustomMetric is the result of a command configured on the daemon. A failed
+// run has no values, message, exit_code and stderr tell why.
+type CustomMetric struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []*CustomValue         `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	State         CollectionState        `protobuf:"varint,3,opt,name=state,proto3,enum=systemmonitor.CollectionState" json:"state,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"` // -1 when the command did not exit by itself
+	Stderr        string                 `protobuf:"bytes,6,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomMetric) Reset() {
+	*x = CustomMetric{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomMetric) ProtoMessage() {}
+
+func (x *CustomMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomMetric.ProtoReflect.Descriptor instead.
+func (*CustomMetric) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CustomMetric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CustomMetric) GetValues() []*CustomValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *CustomMetric) GetState() CollectionState {
+	if x != nil {
+		return x.State
+	}
+	return CollectionState_COLLECTION_STATE_UNSPECIFIED
+}
+
+func (x *CustomMetric) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CustomMetric) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *CustomMetric) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+type CustomValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"` // empty for an output of a single number
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomValue) Reset() {
+	*x = CustomValue{}
+	mi := &file_monitor_system_monitor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomValue) ProtoMessage() {}
+
+func (x *CustomValue) ProtoReflect() protoreflect.Message {
+	mi := &file_monitor_system_monitor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomValue.ProtoReflect.Descriptor instead.
+func (*CustomValue) Descriptor() ([]byte, []int) {
+	return file_monitor_system_monitor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CustomValue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CustomValue) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 var File_monitor_system_monitor_proto protoreflect.FileDescriptor
 
 const file_monitor_system_monitor_proto_rawDesc = "" +
 	"\n" +
-	"\x1cmonitor/system_monitor.proto\x12\rsystemmonitor\"g\n" +
+	"\x1cmonitor/system_monitor.proto\x12\rsystemmonitor\"\xa6\x01\n" +
 	"\x13SubscriptionRequest\x12)\n" +
 	"\x10interval_seconds\x18\x01 \x01(\rR\x0fintervalSeconds\x12%\n" +
-	"\x0ewindow_seconds\x18\x02 \x01(\rR\rwindowSeconds\"\xb3\x03\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\rR\rwindowSeconds\x12=\n" +
+	"\n" +
+	"categories\x18\x03 \x03(\x0e2\x1d.systemmonitor.MetricCategoryR\n" +
+	"categories\"\xaa\a\n" +
 	"\x0eSystemSnapshot\x125\n" +
 	"\bload_avg\x18\x01 \x01(\v2\x1a.systemmonitor.LoadAverageR\aloadAvg\x124\n" +
 	"\tcpu_usage\x18\x02 \x01(\v2\x17.systemmonitor.CpuUsageR\bcpuUsage\x127\n" +
@@ -658,15 +1819,81 @@ const file_monitor_system_monitor_proto_rawDesc = "" +
 	"\x10protocol_traffic\x18\x05 \x03(\v2\x1e.systemmonitor.ProtocolTrafficR\x0fprotocolTraffic\x12B\n" +
 	"\vconnections\x18\x06 \x03(\v2 .systemmonitor.NetworkConnectionR\vconnections\x129\n" +
 	"\vtop_talkers\x18\a \x03(\v2\x18.systemmonitor.TopTalkerR\n" +
-	"topTalkers\"f\n" +
+	"topTalkers\x12;\n" +
+	"\n" +
+	"tcp_states\x18\b \x03(\v2\x1c.systemmonitor.TcpStateCountR\ttcpStates\x12T\n" +
+	"\x17protocol_traffic_source\x18\t \x01(\x0e2\x1c.systemmonitor.TrafficSourceR\x15protocolTrafficSource\x12;\n" +
+	"\bstatuses\x18\n" +
+	" \x03(\v2\x1f.systemmonitor.CollectionStatusR\bstatuses\x12B\n" +
+	"\x0ecustom_metrics\x18\v \x03(\v2\x1b.systemmonitor.CustomMetricR\rcustomMetrics\x122\n" +
+	"\x06memory\x18\f \x01(\v2\x1a.systemmonitor.MemoryStatsR\x06memory\x12=\n" +
+	"\n" +
+	"interfaces\x18\r \x03(\v2\x1d.systemmonitor.InterfaceStatsR\n" +
+	"interfaces\x128\n" +
+	"\bpressure\x18\x0e \x03(\v2\x1c.systemmonitor.PressureStatsR\bpressure\x124\n" +
+	"\acgroups\x18\x0f \x03(\v2\x1a.systemmonitor.CgroupStatsR\acgroups\"\x9d\x01\n" +
+	"\x10CollectionStatus\x129\n" +
+	"\bcategory\x18\x01 \x01(\x0e2\x1d.systemmonitor.MetricCategoryR\bcategory\x124\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x1e.systemmonitor.CollectionStateR\x05state\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xac\x01\n" +
 	"\vLoadAverage\x12\x17\n" +
 	"\aone_min\x18\x01 \x01(\x01R\x06oneMin\x12\x1b\n" +
 	"\tfive_mins\x18\x02 \x01(\x01R\bfiveMins\x12!\n" +
-	"\ffifteen_mins\x18\x03 \x01(\x01R\vfifteenMins\"J\n" +
+	"\ffifteen_mins\x18\x03 \x01(\x01R\vfifteenMins\x12#\n" +
+	"\rrunning_tasks\x18\x04 \x01(\rR\frunningTasks\x12\x1f\n" +
+	"\vtotal_tasks\x18\x05 \x01(\rR\n" +
+	"totalTasks\"\xeb\x01\n" +
 	"\bCpuUsage\x12\x12\n" +
 	"\x04user\x18\x01 \x01(\x01R\x04user\x12\x16\n" +
 	"\x06system\x18\x02 \x01(\x01R\x06system\x12\x12\n" +
-	"\x04idle\x18\x03 \x01(\x01R\x04idle\"S\n" +
+	"\x04idle\x18\x03 \x01(\x01R\x04idle\x12\x12\n" +
+	"\x04nice\x18\x04 \x01(\x01R\x04nice\x12\x16\n" +
+	"\x06iowait\x18\x05 \x01(\x01R\x06iowait\x12\x10\n" +
+	"\x03irq\x18\x06 \x01(\x01R\x03irq\x12\x18\n" +
+	"\asoftirq\x18\a \x01(\x01R\asoftirq\x12\x14\n" +
+	"\x05steal\x18\b \x01(\x01R\x05steal\x121\n" +
+	"\x05cores\x18\t \x03(\v2\x1b.systemmonitor.CpuCoreUsageR\x05cores\"\xd0\x01\n" +
+	"\fCpuCoreUsage\x12\x12\n" +
+	"\x04core\x18\x01 \x01(\tR\x04core\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\x01R\x04user\x12\x16\n" +
+	"\x06system\x18\x03 \x01(\x01R\x06system\x12\x12\n" +
+	"\x04idle\x18\x04 \x01(\x01R\x04idle\x12\x12\n" +
+	"\x04nice\x18\x05 \x01(\x01R\x04nice\x12\x16\n" +
+	"\x06iowait\x18\x06 \x01(\x01R\x06iowait\x12\x10\n" +
+	"\x03irq\x18\a \x01(\x01R\x03irq\x12\x18\n" +
+	"\asoftirq\x18\b \x01(\x01R\asoftirq\x12\x14\n" +
+	"\x05steal\x18\t \x01(\x01R\x05steal\"\xc3\x02\n" +
+	"\vMemoryStats\x12\x19\n" +
+	"\btotal_mb\x18\x01 \x01(\x04R\atotalMb\x12!\n" +
+	"\favailable_mb\x18\x02 \x01(\x04R\vavailableMb\x12\x17\n" +
+	"\aused_mb\x18\x03 \x01(\x04R\x06usedMb\x12\"\n" +
+	"\rbuff_cache_mb\x18\x04 \x01(\x04R\vbuffCacheMb\x12\x19\n" +
+	"\bdirty_mb\x18\x05 \x01(\x04R\adirtyMb\x12\"\n" +
+	"\rswap_total_mb\x18\x06 \x01(\x04R\vswapTotalMb\x12 \n" +
+	"\fswap_used_mb\x18\a \x01(\x04R\n" +
+	"swapUsedMb\x12*\n" +
+	"\x12swap_in_kb_per_sec\x18\b \x01(\x01R\x0eswapInKbPerSec\x12,\n" +
+	"\x13swap_out_kb_per_sec\x18\t \x01(\x01R\x0fswapOutKbPerSec\"\x8d\x01\n" +
+	"\rPressureStats\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12/\n" +
+	"\x04some\x18\x02 \x01(\v2\x1b.systemmonitor.PressureLineR\x04some\x12/\n" +
+	"\x04full\x18\x03 \x01(\v2\x1b.systemmonitor.PressureLineR\x04full\"\x8b\x01\n" +
+	"\fPressureLine\x12\x18\n" +
+	"\apercent\x18\x01 \x01(\x01R\apercent\x12\x14\n" +
+	"\x05avg10\x18\x02 \x01(\x01R\x05avg10\x12\x14\n" +
+	"\x05avg60\x18\x03 \x01(\x01R\x05avg60\x12\x16\n" +
+	"\x06avg300\x18\x04 \x01(\x01R\x06avg300\x12\x1d\n" +
+	"\n" +
+	"total_usec\x18\x05 \x01(\x04R\ttotalUsec\"\xf5\x01\n" +
+	"\vCgroupStats\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1f\n" +
+	"\vcpu_percent\x18\x02 \x01(\x01R\n" +
+	"cpuPercent\x12\x1b\n" +
+	"\tmemory_mb\x18\x03 \x01(\x04R\bmemoryMb\x12&\n" +
+	"\x0fmemory_limit_mb\x18\x04 \x01(\x04R\rmemoryLimitMb\x12*\n" +
+	"\x12io_read_kb_per_sec\x18\x05 \x01(\x01R\x0eioReadKbPerSec\x12,\n" +
+	"\x13io_write_kb_per_sec\x18\x06 \x01(\x01R\x0fioWriteKbPerSec\x12\x12\n" +
+	"\x04pids\x18\a \x01(\x04R\x04pids\"S\n" +
 	"\tDiskStats\x12\x16\n" +
 	"\x06device\x18\x01 \x01(\tR\x06device\x12\x10\n" +
 	"\x03tps\x18\x02 \x01(\x01R\x03tps\x12\x1c\n" +
@@ -681,7 +1908,21 @@ const file_monitor_system_monitor_proto_rawDesc = "" +
 	"\finodes_total\x18\x05 \x01(\x04R\vinodesTotal\x12\x1f\n" +
 	"\vinodes_used\x18\x06 \x01(\x04R\n" +
 	"inodesUsed\x12.\n" +
-	"\x13inodes_used_percent\x18\a \x01(\x01R\x11inodesUsedPercent\"]\n" +
+	"\x13inodes_used_percent\x18\a \x01(\x01R\x11inodesUsedPercent\"\xad\x03\n" +
+	"\x0eInterfaceStats\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x1d\n" +
+	"\n" +
+	"speed_mbps\x18\x03 \x01(\x04R\tspeedMbps\x12'\n" +
+	"\x10rx_bytes_per_sec\x18\x04 \x01(\x01R\rrxBytesPerSec\x12'\n" +
+	"\x10tx_bytes_per_sec\x18\x05 \x01(\x01R\rtxBytesPerSec\x12+\n" +
+	"\x12rx_packets_per_sec\x18\x06 \x01(\x01R\x0frxPacketsPerSec\x12+\n" +
+	"\x12tx_packets_per_sec\x18\a \x01(\x01R\x0ftxPacketsPerSec\x12)\n" +
+	"\x11rx_errors_per_sec\x18\b \x01(\x01R\x0erxErrorsPerSec\x12)\n" +
+	"\x11tx_errors_per_sec\x18\t \x01(\x01R\x0etxErrorsPerSec\x12'\n" +
+	"\x10rx_drops_per_sec\x18\n" +
+	" \x01(\x01R\rrxDropsPerSec\x12'\n" +
+	"\x10tx_drops_per_sec\x18\v \x01(\x01R\rtxDropsPerSec\"]\n" +
 	"\x0fProtocolTraffic\x12\x1a\n" +
 	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x14\n" +
 	"\x05bytes\x18\x02 \x01(\x04R\x05bytes\x12\x18\n" +
@@ -696,7 +1937,46 @@ const file_monitor_system_monitor_proto_rawDesc = "" +
 	"\x03src\x18\x01 \x01(\tR\x03src\x12\x10\n" +
 	"\x03dst\x18\x02 \x01(\tR\x03dst\x12\x1a\n" +
 	"\bprotocol\x18\x03 \x01(\tR\bprotocol\x12\x10\n" +
-	"\x03bps\x18\x04 \x01(\x04R\x03bps2a\n" +
+	"\x03bps\x18\x04 \x01(\x04R\x03bps\"S\n" +
+	"\rTcpStateCount\x12\x16\n" +
+	"\x06family\x18\x01 \x01(\tR\x06family\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x01R\x05count\"\xdb\x01\n" +
+	"\fCustomMetric\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x122\n" +
+	"\x06values\x18\x02 \x03(\v2\x1a.systemmonitor.CustomValueR\x06values\x124\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1e.systemmonitor.CollectionStateR\x05state\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x1b\n" +
+	"\texit_code\x18\x05 \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06stderr\x18\x06 \x01(\tR\x06stderr\"5\n" +
+	"\vCustomValue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value*\xc1\x03\n" +
+	"\x0eMetricCategory\x12\x1f\n" +
+	"\x1bMETRIC_CATEGORY_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cMETRIC_CATEGORY_LOAD_AVERAGE\x10\x01\x12\x17\n" +
+	"\x13METRIC_CATEGORY_CPU\x10\x02\x12\x18\n" +
+	"\x14METRIC_CATEGORY_DISK\x10\x03\x12\x1e\n" +
+	"\x1aMETRIC_CATEGORY_FILESYSTEM\x10\x04\x12\x1f\n" +
+	"\x1bMETRIC_CATEGORY_CONNECTIONS\x10\x05\x12\x1e\n" +
+	"\x1aMETRIC_CATEGORY_TCP_STATES\x10\x06\x12$\n" +
+	" METRIC_CATEGORY_PROTOCOL_TRAFFIC\x10\a\x12\x1f\n" +
+	"\x1bMETRIC_CATEGORY_TOP_TALKERS\x10\b\x12\x1a\n" +
+	"\x16METRIC_CATEGORY_CUSTOM\x10\t\x12\x1a\n" +
+	"\x16METRIC_CATEGORY_MEMORY\x10\n" +
+	"\x12\x1e\n" +
+	"\x1aMETRIC_CATEGORY_INTERFACES\x10\v\x12\x1c\n" +
+	"\x18METRIC_CATEGORY_PRESSURE\x10\f\x12\x1b\n" +
+	"\x17METRIC_CATEGORY_CGROUPS\x10\r*f\n" +
+	"\rTrafficSource\x12\x1e\n" +
+	"\x1aTRAFFIC_SOURCE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16TRAFFIC_SOURCE_CAPTURE\x10\x01\x12\x19\n" +
+	"\x15TRAFFIC_SOURCE_PROCFS\x10\x02*\x8a\x01\n" +
+	"\x0fCollectionState\x12 \n" +
+	"\x1cCOLLECTION_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13COLLECTION_STATE_OK\x10\x01\x12 \n" +
+	"\x1cCOLLECTION_STATE_UNAVAILABLE\x10\x02\x12\x1a\n" +
+	"\x16COLLECTION_STATE_ERROR\x10\x032a\n" +
 	"\rSystemMonitor\x12P\n" +
 	"\tSubscribe\x12\".systemmonitor.SubscriptionRequest\x1a\x1d.systemmonitor.SystemSnapshot0\x01B9Z7github.com/dimryb/system-monitor/internal/proto/monitorb\x06proto3"
 
@@ -712,33 +1992,63 @@ func file_monitor_system_monitor_proto_rawDescGZIP() []byte {
 	return file_monitor_system_monitor_proto_rawDescData
 }
 
-var file_monitor_system_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_monitor_system_monitor_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_monitor_system_monitor_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_monitor_system_monitor_proto_goTypes = []any{
-	(*SubscriptionRequest)(nil), // 0: systemmonitor.SubscriptionRequest
-	(*SystemSnapshot)(nil),      // 1: systemmonitor.SystemSnapshot
-	(*LoadAverage)(nil),         // 2: systemmonitor.LoadAverage
-	(*CpuUsage)(nil),            // 3: systemmonitor.CpuUsage
-	(*DiskStats)(nil),           // 4: systemmonitor.DiskStats
-	(*FsUsage)(nil),             // 5: systemmonitor.FsUsage
-	(*ProtocolTraffic)(nil),     // 6: systemmonitor.ProtocolTraffic
-	(*NetworkConnection)(nil),   // 7: systemmonitor.NetworkConnection
-	(*TopTalker)(nil),           // 8: systemmonitor.TopTalker
+	(MetricCategory)(0),         // 0: systemmonitor.MetricCategory
+	(TrafficSource)(0),          // 1: systemmonitor.TrafficSource
+	(CollectionState)(0),        // 2: systemmonitor.CollectionState
+	(*SubscriptionRequest)(nil), // 3: systemmonitor.SubscriptionRequest
+	(*SystemSnapshot)(nil),      // 4: systemmonitor.SystemSnapshot
+	(*CollectionStatus)(nil),    // 5: systemmonitor.CollectionStatus
+	(*LoadAverage)(nil),         // 6: systemmonitor.LoadAverage
+	(*CpuUsage)(nil),            // 7: systemmonitor.CpuUsage
+	(*CpuCoreUsage)(nil),        // 8: systemmonitor.CpuCoreUsage
+	(*MemoryStats)(nil),         // 9: systemmonitor.MemoryStats
+	(*PressureStats)(nil),       // 10: systemmonitor.PressureStats
+	(*PressureLine)(nil),        // 11: systemmonitor.PressureLine
+	(*CgroupStats)(nil),         // 12: systemmonitor.CgroupStats
+	(*DiskStats)(nil),           // 13: systemmonitor.DiskStats
+	(*FsUsage)(nil),             // 14: systemmonitor.FsUsage
+	(*InterfaceStats)(nil),      // 15: systemmonitor.InterfaceStats
+	(*ProtocolTraffic)(nil),     // 16: systemmonitor.ProtocolTraffic
+	(*NetworkConnection)(nil),   // 17: systemmonitor.NetworkConnection
+	(*TopTalker)(nil),           // 18: systemmonitor.TopTalker
+	(*TcpStateCount)(nil),       // 19: systemmonitor.TcpStateCount
+	(*CustomMetric)(nil),        // 20: systemmonitor.CustomMetric
+	(*CustomValue)(nil),         // 21: systemmonitor.CustomValue
 }
 var file_monitor_system_monitor_proto_depIdxs = []int32{
-	2, // 0: systemmonitor.SystemSnapshot.load_avg:type_name -> systemmonitor.LoadAverage
-	3, // 1: systemmonitor.SystemSnapshot.cpu_usage:type_name -> systemmonitor.CpuUsage
-	4, // 2: systemmonitor.SystemSnapshot.disk_stats:type_name -> systemmonitor.DiskStats
-	5, // 3: systemmonitor.SystemSnapshot.fs_usage:type_name -> systemmonitor.FsUsage
-	6, // 4: systemmonitor.SystemSnapshot.protocol_traffic:type_name -> systemmonitor.ProtocolTraffic
-	7, // 5: systemmonitor.SystemSnapshot.connections:type_name -> systemmonitor.NetworkConnection
-	8, // 6: systemmonitor.SystemSnapshot.top_talkers:type_name -> systemmonitor.TopTalker
-	0, // 7: systemmonitor.SystemMonitor.Subscribe:input_type -> systemmonitor.SubscriptionRequest
-	1, // 8: systemmonitor.SystemMonitor.Subscribe:output_type -> systemmonitor.SystemSnapshot
-	8, // [8:9] is the sub-list for method output_type
-	7, // [7:8] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	0,  // 0: systemmonitor.SubscriptionRequest.categories:type_name -> systemmonitor.MetricCategory
+	6,  // 1: systemmonitor.SystemSnapshot.load_avg:type_name -> systemmonitor.LoadAverage
+	7,  // 2: systemmonitor.SystemSnapshot.cpu_usage:type_name -> systemmonitor.CpuUsage
+	13, // 3: systemmonitor.SystemSnapshot.disk_stats:type_name -> systemmonitor.DiskStats
+	14, // 4: systemmonitor.SystemSnapshot.fs_usage:type_name -> systemmonitor.FsUsage
+	16, // 5: systemmonitor.SystemSnapshot.protocol_traffic:type_name -> systemmonitor.ProtocolTraffic
+	17, // 6: systemmonitor.SystemSnapshot.connections:type_name -> systemmonitor.NetworkConnection
+	18, // 7: systemmonitor.SystemSnapshot.top_talkers:type_name -> systemmonitor.TopTalker
+	19, // 8: systemmonitor.SystemSnapshot.tcp_states:type_name -> systemmonitor.TcpStateCount
+	1,  // 9: systemmonitor.SystemSnapshot.protocol_traffic_source:type_name -> systemmonitor.TrafficSource
+	5,  // 10: systemmonitor.SystemSnapshot.statuses:type_name -> systemmonitor.CollectionStatus
+	20, // 11: systemmonitor.SystemSnapshot.custom_metrics:type_name -> systemmonitor.CustomMetric
+	9,  // 12: systemmonitor.SystemSnapshot.memory:type_name -> systemmonitor.MemoryStats
+	15, // 13: systemmonitor.SystemSnapshot.interfaces:type_name -> systemmonitor.InterfaceStats
+	10, // 14: systemmonitor.SystemSnapshot.pressure:type_name -> systemmonitor.PressureStats
+	12, // 15: systemmonitor.SystemSnapshot.cgroups:type_name -> systemmonitor.CgroupStats
+	0,  // 16: systemmonitor.CollectionStatus.category:type_name -> systemmonitor.MetricCategory
+	2,  // 17: systemmonitor.CollectionStatus.state:type_name -> systemmonitor.CollectionState
+	8,  // 18: systemmonitor.CpuUsage.cores:type_name -> systemmonitor.CpuCoreUsage
+	11, // 19: systemmonitor.PressureStats.some:type_name -> systemmonitor.PressureLine
+	11, // 20: systemmonitor.PressureStats.full:type_name -> systemmonitor.PressureLine
+	21, // 21: systemmonitor.CustomMetric.values:type_name -> systemmonitor.CustomValue
+	2,  // 22: systemmonitor.CustomMetric.state:type_name -> systemmonitor.CollectionState
+	3,  // 23: systemmonitor.SystemMonitor.Subscribe:input_type -> systemmonitor.SubscriptionRequest
+	4,  // 24: systemmonitor.SystemMonitor.Subscribe:output_type -> systemmonitor.SystemSnapshot
+	24, // [24:25] is the sub-list for method output_type
+	23, // [23:24] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_monitor_system_monitor_proto_init() }
@@ -751,13 +2061,14 @@ func file_monitor_system_monitor_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_monitor_system_monitor_proto_rawDesc), len(file_monitor_system_monitor_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      3,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_monitor_system_monitor_proto_goTypes,
 		DependencyIndexes: file_monitor_system_monitor_proto_depIdxs,
+		EnumInfos:         file_monitor_system_monitor_proto_enumTypes,
 		MessageInfos:      file_monitor_system_monitor_proto_msgTypes,
 	}.Build()
 	File_monitor_system_monitor_proto = out.File